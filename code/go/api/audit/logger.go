@@ -2,9 +2,10 @@
 
 import (
 	"context"
+	"maps"
 	"os"
+	"slices"
 
-	"github.com/google/uuid"
 	"github.com/ogen-go/ogen/middleware"
 	"github.com/rs/zerolog"
 
@@ -15,6 +16,10 @@
 
 const (
 	logLevel = "audit"
+
+	// DecisionAllow and DecisionDeny are logged for authorization decisions.
+	DecisionAllow = "allow"
+	DecisionDeny  = "deny"
 )
 
 type (
@@ -63,16 +68,8 @@ func (l logger) Log(ctx context.Context, method string) {
 	// Get request id from context.
 	event = event.Str("request_id", request.GetReqIdCtx(ctx))
 
-	// Add user identity to audit logs.
-	var kid *uuid.UUID
-
-	if ctx != nil {
-		kid = identity.Get(ctx)
-	}
-
-	if kid != nil {
-		event = event.Str("key_id", kid.String())
-	}
+	// Add caller identity to audit logs.
+	event = withPrincipal(ctx, event)
 
 	// Add method to log.
 	event = event.Str("method", method)
@@ -87,16 +84,8 @@ func (l logger) LogP(ctx context.Context, method string, params Parameters) {
 	// Get request id from context.
 	event = event.Str("request_id", request.GetReqIdCtx(ctx))
 
-	// Add user identity to audit logs.
-	var kid *uuid.UUID
-
-	if ctx != nil {
-		kid = identity.Get(ctx)
-	}
-
-	if kid != nil {
-		event = event.Str("key_id", kid.String())
-	}
+	// Add caller identity to audit logs.
+	event = withPrincipal(ctx, event)
 
 	// Add method to log.
 	event = event.Str("method", method)
@@ -109,6 +98,21 @@ func (l logger) LogP(ctx context.Context, method string, params Parameters) {
 	event.Send()
 }
 
+// withPrincipal adds fields of the principal in the context to the event.
+func withPrincipal(ctx context.Context, event *zerolog.Event) *zerolog.Event {
+	if ctx == nil {
+		return event
+	}
+
+	fields := identity.Get(ctx).LogFields()
+
+	for _, k := range slices.Sorted(maps.Keys(fields)) {
+		event = event.Str(k, fields[k])
+	}
+
+	return event
+}
+
 // Middleware returns ogen middleware interface.
 func Middleware(req middleware.Request, next middleware.Next) (middleware.Response, error) {
 	resp, err := next(req)
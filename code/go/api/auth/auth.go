@@ -2,9 +2,13 @@
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 
+	"toll/api/audit"
+	"toll/api/config"
 	apierr "toll/api/handler/errors"
 	"toll/api/identity"
 	"toll/api/restapi"
@@ -18,11 +22,15 @@
 type (
 	Authorization interface {
 		HandleApiKeyAuth(ctx context.Context, operationName string, t restapi.ApiKeyAuth) (context.Context, error)
+		HandleHmacAuth(ctx context.Context, operationName string, t restapi.HmacAuth) (context.Context, error)
+		HandleClientCertAuth(ctx context.Context, operationName string, t restapi.ClientCertAuth) (context.Context, error)
 	}
 
 	authorizer struct {
-		auth service.AuthService
-		log  log.Logger
+		auth     service.AuthService
+		devices  service.DeviceService
+		verifier *verifier
+		log      log.Logger
 	}
 )
 
@@ -35,35 +43,130 @@ func Get() Authorization {
 	if handle == nil {
 		// Create Authorization provider handle with auth service.
 		handle = &authorizer{
-			auth: service.Authorization,
-			log:  log.WithField(types.LogComponent, "api/auth"),
+			auth:    service.Authorization,
+			devices: service.Devices,
+			verifier: &verifier{
+				devices: service.Devices,
+				replay:  newReplayCache(),
+				maxSkew: config.Get().Signing.MaxSkew,
+				now:     time.Now,
+			},
+			log: log.WithField(types.LogComponent, "api/auth"),
 		}
 	}
 
 	return handle
 }
 
+// operationScopes holds scope required by every operation secured with API key.
+var operationScopes = map[string]types.Scope{
+	restapi.RecordTollEventOperation:  types.ScopeEventsWrite,
+	restapi.RecordTollEventsOperation: types.ScopeEventsWrite,
+	restapi.GetTollEventsOperation:    types.ScopeEventsRead,
+	restapi.GetVehicleFeesOperation:   types.ScopeFeesRead,
+	restapi.ListApiKeysOperation:      types.ScopeKeysAdmin,
+	restapi.CreateApiKeyOperation:     types.ScopeKeysAdmin,
+	restapi.UpdateApiKeyOperation:     types.ScopeKeysAdmin,
+	restapi.RevokeApiKeyOperation:     types.ScopeKeysAdmin,
+}
+
 // HandleApiKeyAuth implements openapi spec security definition for API Key Auth.
+// Key should be granted the scope of the operation.
 func (op *authorizer) HandleApiKeyAuth(ctx context.Context, operationName string, t restapi.ApiKeyAuth) (context.Context, error) {
-	ctx, kid, err := op.authApiKey(ctx, t.APIKey)
+	key, err := op.authApiKey(ctx, t.APIKey)
 	if err != nil {
 		log.WithFields(errlog.StackLog(err)).Warne(err, "validate api key")
 
 		return ctx, apierr.ErrAPIUnauthorized
 	}
 
-	return identity.Set(ctx, kid), nil
+	ctx = identity.Set(ctx, identity.FromApiKey(key))
+
+	return op.authorize(ctx, operationName, "API key", key.HasScope)
+}
+
+// HandleHmacAuth implements openapi spec security definition for requests signed
+// by gantry devices. Device should be granted the scope of the operation.
+func (op *authorizer) HandleHmacAuth(ctx context.Context, operationName string, t restapi.HmacAuth) (context.Context, error) {
+	device, err := op.verifier.verify(ctx, t.APIKey)
+	if err != nil {
+		log.WithFields(errlog.StackLog(err)).Warne(err, "verify request signature")
+
+		// Clock and nonce problems are reported so gantries can recover from them.
+		for _, reason := range []error{ErrSignatureSkew, ErrNonceReused} {
+			if errors.Is(err, reason) {
+				return ctx, apierr.ErrAPIUnauthorized.WithDetails("%s", reason)
+			}
+		}
+
+		return ctx, apierr.ErrAPIUnauthorized
+	}
+
+	ctx = identity.Set(ctx, identity.FromDevice(device))
+
+	return op.authorize(ctx, operationName, "device", device.HasScope)
+}
+
+// HandleClientCertAuth implements openapi spec security definition for devices
+// connecting with a client certificate to the mutual TLS listener.
+func (op *authorizer) HandleClientCertAuth(ctx context.Context, operationName string, t restapi.ClientCertAuth) (context.Context, error) {
+	cert := clientCertificate(ctx)
+	if cert == nil || t.APIKey != CertificateFingerprint(cert) {
+		op.log.Warn("client certificate header without verified certificate")
+
+		return ctx, apierr.ErrAPIUnauthorized
+	}
+
+	device, err := op.devices.GetActiveByCertificate(ctx, t.APIKey, cert.Subject.String())
+	if err != nil {
+		log.WithFields(errlog.StackLog(err)).Warne(err, "validate client certificate")
+
+		return ctx, apierr.ErrAPIUnauthorized
+	}
+
+	ctx = identity.Set(ctx, identity.FromDevice(device))
+
+	return op.authorize(ctx, operationName, "device", device.HasScope)
+}
+
+// authorize checks that the caller is granted the scope of the operation and
+// audits the decision.
+func (op *authorizer) authorize(ctx context.Context, operationName, caller string, hasScope func(types.Scope) bool) (context.Context, error) {
+	scope, ok := operationScopes[operationName]
+	if !ok || !hasScope(scope) {
+		audit.LogP(ctx, operationName, audit.Parameters{
+			"scope":    string(scope),
+			"decision": audit.DecisionDeny,
+		})
+
+		if !ok {
+			op.log.Warnf("no scope defined for operation %s", operationName)
+
+			return ctx, apierr.ErrAPIForbidden.
+				WithDetails("operation %s is not permitted", operationName)
+		}
+
+		return ctx, apierr.ErrAPIForbidden.
+			WithDetails("%s is missing scope %s", caller, scope)
+	}
+
+	audit.LogP(ctx, operationName, audit.Parameters{
+		"scope":    string(scope),
+		"decision": audit.DecisionAllow,
+	})
+
+	return ctx, nil
 }
 
-func (op *authorizer) authApiKey(ctx context.Context, token string) (context.Context, *uuid.UUID, error) {
-	kid, err := op.auth.ValidateApiKey(ctx, token)
+func (op *authorizer) authApiKey(ctx context.Context, token string) (*types.ApiKey, error) {
+	key, err := op.auth.ValidateApiKey(ctx, token)
 	if err != nil {
-		return ctx, nil, apierr.ErrAPIUnauthorized
+		return nil, apierr.ErrAPIUnauthorized
 	}
 
-	if kid == nil || *kid == uuid.Nil {
-		return ctx, nil, apierr.ErrAPIUnauthorized
+	if key == nil || key.Id == uuid.Nil {
+		return nil, apierr.ErrAPIUnauthorized
 	}
 
-	return ctx, kid, nil
+	return key, nil
 }
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	apierr "toll/api/handler/errors"
+	"toll/api/identity"
+	"toll/api/restapi"
+	service "toll/api/service/mocks"
+	"toll/api/types"
+
+	"toll/internal/log"
+	"toll/internal/test"
+)
+
+func TestHandleApiKeyAuth(t *testing.T) {
+	t.Parallel()
+
+	kid := uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d")
+
+	tests := []struct {
+		name      string
+		operation string
+		key       *types.ApiKey
+		err       error
+	}{
+		{"granted", restapi.RecordTollEventOperation, &types.ApiKey{Id: kid, Scopes: types.Scopes{types.ScopeEventsWrite}}, nil},
+		{"missing scope", restapi.GetVehicleFeesOperation, &types.ApiKey{Id: kid, Scopes: types.Scopes{types.ScopeEventsWrite}}, nil},
+		{"admin without system key", restapi.ListApiKeysOperation, &types.ApiKey{Id: kid, Scopes: types.Scopes{types.ScopeKeysAdmin}}, nil},
+		{"admin", restapi.ListApiKeysOperation, &types.ApiKey{Id: kid, Scopes: types.Scopes{types.ScopeKeysAdmin}, SystemKey: true}, nil},
+		{"unknown operation", "Unknown", &types.ApiKey{Id: kid, Scopes: types.Scopes{types.ScopeKeysAdmin}, SystemKey: true}, nil},
+		{"invalid key", restapi.RecordTollEventOperation, nil, errors.New("api key is expired")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Define mocked executions.
+			auth := service.NewMockAuthService(t)
+			auth.EXPECT().ValidateApiKey(t.Context(), "secret").Return(tt.key, tt.err)
+
+			op := &authorizer{auth: auth, log: log.Noop()}
+
+			ctx, err := op.HandleApiKeyAuth(t.Context(), tt.operation, restapi.ApiKeyAuth{APIKey: "secret"})
+
+			var apiErr *apierr.APIError
+			errors.As(err, &apiErr)
+
+			test.Match(t, identity.Get(ctx), apiErr)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"net/http"
+)
+
+// ClientCertificateHeader carries fingerprint of the verified client certificate.
+const ClientCertificateHeader = "X-Client-Certificate"
+
+type clientCertKey struct{}
+
+// ServerTLS returns TLS config of a listener requiring client certificates signed
+// by one of the CAs.
+func ServerTLS(clientCAs *x509.CertPool) *tls.Config {
+	return &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+}
+
+// ClientCertificate replaces the client certificate header with the fingerprint of
+// the certificate verified by the TLS listener, so the security handler is called
+// for it. Headers sent by clients are always discarded.
+func ClientCertificate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Del(ClientCertificateHeader)
+
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+			cert := r.TLS.VerifiedChains[0][0]
+
+			r.Header.Set(ClientCertificateHeader, CertificateFingerprint(cert))
+			r = r.WithContext(context.WithValue(r.Context(), clientCertKey{}, cert))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CertificateFingerprint returns hex SHA-256 of the certificate public key, it is
+// kept when the certificate is renewed with the same key.
+func CertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// clientCertificate returns the verified client certificate of the request.
+func clientCertificate(ctx context.Context) *x509.Certificate {
+	cert, _ := ctx.Value(clientCertKey{}).(*x509.Certificate)
+
+	return cert
+}
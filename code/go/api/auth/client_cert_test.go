@@ -0,0 +1,263 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
+
+	apierr "toll/api/handler/errors"
+	"toll/api/identity"
+	"toll/api/restapi"
+	service "toll/api/service/mocks"
+	"toll/api/types"
+
+	"toll/internal/log"
+	"toll/internal/test"
+)
+
+// testCA is a locally generated certificate authority.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCA{cert: cert, key: key}
+}
+
+// issue returns certificate signed by the CA for a server or a client.
+func (ca *testCA) issue(t *testing.T, name string, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name, Organization: []string{"Toll"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// pool returns cert pool trusting the CA.
+func (ca *testCA) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+
+	return pool
+}
+
+// request holds what handler behind ClientCertificate middleware received.
+type request struct {
+	header string
+	ctx    context.Context
+}
+
+// mtlsServer starts TLS server requiring client certificates signed by the CA.
+func mtlsServer(t *testing.T, ca *testCA, received chan<- request) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewUnstartedServer(ClientCertificate(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		received <- request{header: r.Header.Get(ClientCertificateHeader), ctx: r.Context()}
+	})))
+
+	srv.TLS = ServerTLS(ca.pool())
+	srv.TLS.Certificates = []tls.Certificate{ca.issue(t, "toll-api", x509.ExtKeyUsageServerAuth)}
+	srv.StartTLS()
+
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// mtlsClient returns client trusting the CA and presenting the certificates.
+func mtlsClient(ca *testCA, certs ...tls.Certificate) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:      ca.pool(),
+				Certificates: certs,
+				MinVersion:   tls.VersionTLS12,
+			},
+		},
+	}
+}
+
+func TestClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t, "toll-devices")
+	other := newTestCA(t, "other")
+
+	cert := ca.issue(t, "G-17", x509.ExtKeyUsageClientAuth)
+
+	received := make(chan request, 1)
+	srv := mtlsServer(t, ca, received)
+
+	tests := []struct {
+		name   string
+		client *http.Client
+	}{
+		{"trusted certificate", mtlsClient(ca, cert)},
+		{"no certificate", mtlsClient(ca)},
+		{"untrusted certificate", mtlsClient(ca, other.issue(t, "G-17", x509.ExtKeyUsageClientAuth))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, srv.URL, nil)
+			req.Header.Set(ClientCertificateHeader, "spoofed")
+
+			resp, err := tt.client.Do(req)
+			if err != nil {
+				test.Match(t, false)
+
+				return
+			}
+
+			resp.Body.Close()
+
+			r := <-received
+
+			test.Match(t,
+				r.header == CertificateFingerprint(cert.Leaf),
+				clientCertificate(r.ctx).Subject.String(),
+			)
+		})
+	}
+}
+
+func TestClientCertificate_Plain(t *testing.T) {
+	t.Parallel()
+
+	var header string
+
+	h := ClientCertificate(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(ClientCertificateHeader)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/toll-events", nil)
+	req.Header.Set(ClientCertificateHeader, "spoofed")
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	test.Match(t, header)
+}
+
+func TestHandleClientCertAuth(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t, "toll-devices")
+	cert := ca.issue(t, "G-17", x509.ExtKeyUsageClientAuth)
+
+	device := &types.Device{
+		Id:     uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+		Scopes: types.Scopes{types.ScopeEventsWrite},
+	}
+
+	tests := []struct {
+		name      string
+		operation string
+		header    string
+		device    *types.Device
+		err       error
+	}{
+		{"granted", restapi.RecordTollEventOperation, CertificateFingerprint(cert.Leaf), device, nil},
+		{"missing scope", restapi.GetVehicleFeesOperation, CertificateFingerprint(cert.Leaf), device, nil},
+		{"unknown device", restapi.RecordTollEventOperation, CertificateFingerprint(cert.Leaf), nil, errors.New("unknown device")},
+		{"fingerprint mismatch", restapi.RecordTollEventOperation, "spoofed", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Define mocked executions.
+			devices := service.NewMockDeviceService(t)
+			devices.EXPECT().
+				GetActiveByCertificate(mock.Anything, CertificateFingerprint(cert.Leaf), "CN=G-17,O=Toll").
+				Return(tt.device, tt.err).
+				Maybe()
+
+			op := &authorizer{devices: devices, log: log.Noop()}
+
+			ctx := context.WithValue(t.Context(), clientCertKey{}, cert.Leaf)
+
+			ctx, err := op.HandleClientCertAuth(ctx, tt.operation, restapi.ClientCertAuth{APIKey: tt.header})
+
+			var apiErr *apierr.APIError
+			errors.As(err, &apiErr)
+
+			test.Match(t, identity.Get(ctx), apiErr)
+		})
+	}
+}
+
+func TestHandleClientCertAuth_NoCertificate(t *testing.T) {
+	t.Parallel()
+
+	op := &authorizer{devices: service.NewMockDeviceService(t), log: log.Noop()}
+
+	ctx, err := op.HandleClientCertAuth(t.Context(), restapi.RecordTollEventOperation, restapi.ClientCertAuth{APIKey: "spoofed"})
+
+	test.Match(t, identity.Get(ctx), err)
+}
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// replaySweepInterval is the minimal time between sweeps of expired nonces.
+const replaySweepInterval = time.Minute
+
+// replayCache remembers nonces used on this replica until their signatures expire.
+// It is checked before the nonces shared by replicas in the database.
+type replayCache struct {
+	mu      sync.Mutex
+	seen    map[string]time.Time
+	sweepAt time.Time
+}
+
+func newReplayCache() *replayCache {
+	return &replayCache{
+		seen: make(map[string]time.Time),
+	}
+}
+
+// add records the nonce until expiry, returns false when it is already recorded.
+func (c *replayCache) add(nonce string, expires, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.After(c.sweepAt) {
+		for k, exp := range c.seen {
+			if now.After(exp) {
+				delete(c.seen, k)
+			}
+		}
+
+		c.sweepAt = now.Add(replaySweepInterval)
+	}
+
+	if exp, ok := c.seen[nonce]; ok && !now.After(exp) {
+		return false
+	}
+
+	c.seen[nonce] = expires
+
+	return true
+}
+
+// remove forgets the nonce, so it can be recorded again.
+func (c *replayCache) remove(nonce string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.seen, nonce)
+}
@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"toll/api/service"
+	"toll/api/types"
+
+	"toll/internal/errlog"
+)
+
+const (
+	// SignatureHeader carries the device signature of the request.
+	SignatureHeader = "X-Signature"
+
+	// maxSignedBody limits the body read to compute its digest.
+	maxSignedBody = 16 << 20
+
+	// maxNonceLength limits the nonce kept in the replay cache.
+	maxNonceLength = 128
+)
+
+var (
+	// ErrSignatureFormat is returned when signature header cannot be parsed.
+	ErrSignatureFormat = errors.New("malformed signature")
+
+	// ErrSignatureMismatch is returned when signature does not match the request.
+	ErrSignatureMismatch = errors.New("signature does not match request")
+
+	// ErrSignatureSkew is returned when signature timestamp is outside of the clock skew window.
+	ErrSignatureSkew = errors.New("signature timestamp is outside of allowed clock skew")
+
+	// ErrNonceReused is returned when signature nonce was already used by the device.
+	ErrNonceReused = errors.New("signature nonce was already used")
+
+	// ErrUnsignedBody is returned when request body was not digested before verification.
+	ErrUnsignedBody = errors.New("request body was not digested")
+)
+
+type (
+	signedRequestKey struct{}
+
+	// signedRequest holds parts of the request covered by the signature.
+	signedRequest struct {
+		method string
+		uri    string
+		digest []byte
+	}
+
+	// signature is the parsed signature header.
+	signature struct {
+		device    uuid.UUID
+		timestamp string
+		nonce     string
+		mac       []byte
+	}
+
+	// verifier checks request signatures of gantry devices.
+	verifier struct {
+		devices service.DeviceService
+		replay  *replayCache
+		maxSkew time.Duration
+		now     func() time.Time
+	}
+)
+
+// Signed computes the body digest of requests carrying a signature. Security
+// handlers run before the body is decoded, so the body is read here and put back
+// for decoding, and the security handler verifies the signature over the digest.
+func Signed(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(SignatureHeader) == "" {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBody))
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		if err == nil {
+			digest := sha256.Sum256(body)
+
+			r = r.WithContext(context.WithValue(r.Context(), signedRequestKey{}, &signedRequest{
+				method: r.Method,
+				uri:    r.RequestURI,
+				digest: digest[:],
+			}))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Sign returns signature header value of the request made by the device.
+func Sign(device uuid.UUID, secret, method, uri string, body []byte, at time.Time, nonce string) string {
+	digest := sha256.Sum256(body)
+	ts := strconv.FormatInt(at.Unix(), 10)
+
+	mac := signRequest(secret, &signedRequest{method: method, uri: uri, digest: digest[:]}, ts, nonce)
+
+	return "device=" + device.String() + ",ts=" + ts + ",nonce=" + nonce + ",sig=" + hex.EncodeToString(mac)
+}
+
+// signRequest returns HMAC-SHA256 of the signed request parts.
+func signRequest(secret string, req *signedRequest, ts, nonce string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join([]string{
+		req.method,
+		req.uri,
+		ts,
+		nonce,
+		hex.EncodeToString(req.digest),
+	}, "\n")))
+
+	return mac.Sum(nil)
+}
+
+// parseSignature parses comma separated key=value pairs of the signature header.
+func parseSignature(value string) (*signature, error) {
+	ret := &signature{}
+
+	for _, part := range strings.Split(value, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, errlog.Error(ErrSignatureFormat)
+		}
+
+		var err error
+
+		switch k {
+		case "device":
+			ret.device, err = uuid.Parse(v)
+		case "ts":
+			_, err = strconv.ParseInt(v, 10, 64)
+			ret.timestamp = v
+		case "nonce":
+			ret.nonce = v
+		case "sig":
+			ret.mac, err = hex.DecodeString(v)
+		}
+
+		if err != nil {
+			return nil, errlog.Error(ErrSignatureFormat)
+		}
+	}
+
+	if ret.device == uuid.Nil || ret.timestamp == "" || ret.nonce == "" || len(ret.mac) == 0 {
+		return nil, errlog.Error(ErrSignatureFormat)
+	}
+
+	if len(ret.nonce) > maxNonceLength {
+		return nil, errlog.Error(ErrSignatureFormat)
+	}
+
+	return ret, nil
+}
+
+// verify returns the device which signed the request in the context.
+func (v *verifier) verify(ctx context.Context, header string) (*types.Device, error) {
+	req, ok := ctx.Value(signedRequestKey{}).(*signedRequest)
+	if !ok {
+		return nil, errlog.Error(ErrUnsignedBody)
+	}
+
+	sig, err := parseSignature(header)
+	if err != nil {
+		return nil, err
+	}
+
+	ts, _ := strconv.ParseInt(sig.timestamp, 10, 64)
+	signedAt := time.Unix(ts, 0)
+
+	now := v.now()
+	if signedAt.Before(now.Add(-v.maxSkew)) || signedAt.After(now.Add(v.maxSkew)) {
+		return nil, errlog.Error(ErrSignatureSkew)
+	}
+
+	device, err := v.devices.GetActive(ctx, sig.device)
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	// Devices using client certificates have no secret to sign with.
+	if device.Secret == "" {
+		return nil, errlog.Error(ErrSignatureMismatch)
+	}
+
+	if !hmac.Equal(sig.mac, signRequest(device.Secret, req, sig.timestamp, sig.nonce)) {
+		return nil, errlog.Error(ErrSignatureMismatch)
+	}
+
+	// Nonce is recorded only for valid signatures, it is rejected by the skew
+	// window once it expires. The local cache rejects replays to this replica
+	// without a database round trip, the database replays to other replicas.
+	// A nonce not claimed because of a database error is forgotten, so the device
+	// can retry the request with it.
+	expires := signedAt.Add(v.maxSkew)
+	key := device.Id.String() + ":" + sig.nonce
+
+	if !v.replay.add(key, expires, now) {
+		return nil, errlog.Error(ErrNonceReused)
+	}
+
+	claimed, err := v.devices.ClaimNonce(ctx, device.Id, sig.nonce, expires, now)
+	if err != nil {
+		v.replay.remove(key)
+
+		return nil, errlog.Error(err)
+	}
+
+	if !claimed {
+		return nil, errlog.Error(ErrNonceReused)
+	}
+
+	return device, nil
+}
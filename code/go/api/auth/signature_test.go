@@ -0,0 +1,313 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
+
+	apierr "toll/api/handler/errors"
+	"toll/api/identity"
+	"toll/api/restapi"
+	service "toll/api/service/mocks"
+	"toll/api/types"
+
+	"toll/internal/log"
+	"toll/internal/test"
+)
+
+// signedContext returns context of the request digested by Signed middleware.
+func signedContext(t *testing.T, method, uri, body string) context.Context {
+	t.Helper()
+
+	var ctx context.Context
+
+	h := Signed(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}))
+
+	req := httptest.NewRequest(method, uri, strings.NewReader(body))
+	req.Header.Set(SignatureHeader, "set")
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	return ctx
+}
+
+func TestSigned_KeepsBody(t *testing.T) {
+	t.Parallel()
+
+	var body []byte
+
+	h := Signed(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/toll-events", strings.NewReader(`{"a":1}`))
+	req.Header.Set(SignatureHeader, "set")
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	test.Match(t, string(body))
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 2, 3, 12, 0, 0, 0, time.UTC)
+	device := &types.Device{
+		Id:     uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+		Secret: "device-secret",
+		Scopes: types.Scopes{types.ScopeEventsWrite},
+	}
+	body := `{"license_plate":"ABC123"}`
+
+	tests := []struct {
+		name   string
+		header string
+		body   string
+		err    error
+	}{
+		{"valid", Sign(device.Id, device.Secret, http.MethodPost, "/api/v1/toll-events", []byte(body), now, "n1"), body, nil},
+		{"tampered body", Sign(device.Id, device.Secret, http.MethodPost, "/api/v1/toll-events", []byte(body), now, "n1"), `{"license_plate":"XYZ999"}`, nil},
+		{"wrong secret", Sign(device.Id, "other", http.MethodPost, "/api/v1/toll-events", []byte(body), now, "n1"), body, nil},
+		{"wrong path", Sign(device.Id, device.Secret, http.MethodPost, "/api/v1/toll-events:batch", []byte(body), now, "n1"), body, nil},
+		{"stale", Sign(device.Id, device.Secret, http.MethodPost, "/api/v1/toll-events", []byte(body), now.Add(-6*time.Minute), "n1"), body, nil},
+		{"future", Sign(device.Id, device.Secret, http.MethodPost, "/api/v1/toll-events", []byte(body), now.Add(6*time.Minute), "n1"), body, nil},
+		{"malformed", "device=nope,ts=1,nonce=n1,sig=00", body, nil},
+		{"missing nonce", "device=" + device.Id.String() + ",ts=1,sig=00", body, nil},
+		{"unknown device", Sign(device.Id, device.Secret, http.MethodPost, "/api/v1/toll-events", []byte(body), now, "n1"), body, errors.New("unknown device")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Define mocked executions.
+			devices := service.NewMockDeviceService(t)
+			devices.EXPECT().GetActive(mock.Anything, device.Id).Return(device, tt.err).Maybe()
+			devices.EXPECT().ClaimNonce(mock.Anything, device.Id, "n1", mock.Anything, now).Return(true, nil).Maybe()
+
+			v := &verifier{
+				devices: devices,
+				replay:  newReplayCache(),
+				maxSkew: 5 * time.Minute,
+				now:     func() time.Time { return now },
+			}
+
+			ctx := signedContext(t, http.MethodPost, "/api/v1/toll-events", tt.body)
+
+			ret, err := v.verify(ctx, tt.header)
+
+			test.Match(t, ret != nil, errorReason(err))
+		})
+	}
+}
+
+func TestVerify_Replay(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 2, 3, 12, 0, 0, 0, time.UTC)
+	device := &types.Device{
+		Id:     uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+		Secret: "device-secret",
+	}
+
+	// Define mocked executions.
+	devices := service.NewMockDeviceService(t)
+	devices.EXPECT().GetActive(mock.Anything, device.Id).Return(device, nil)
+
+	// Replay to the same replica is rejected by the cache before the database.
+	devices.EXPECT().ClaimNonce(mock.Anything, device.Id, "n1", mock.Anything, now).Return(true, nil).Once()
+
+	v := &verifier{
+		devices: devices,
+		replay:  newReplayCache(),
+		maxSkew: 5 * time.Minute,
+		now:     func() time.Time { return now },
+	}
+
+	ctx := signedContext(t, http.MethodPost, "/api/v1/toll-events", "{}")
+	header := Sign(device.Id, device.Secret, http.MethodPost, "/api/v1/toll-events", []byte("{}"), now, "n1")
+
+	_, errFirst := v.verify(ctx, header)
+	_, errReplay := v.verify(ctx, header)
+
+	// Nonce is forgotten once the signature is outside of the skew window.
+	v.now = func() time.Time { return now.Add(10 * time.Minute) }
+	_, errLate := v.verify(ctx, header)
+
+	test.Match(t, errorReason(errFirst), errorReason(errReplay), errorReason(errLate))
+}
+
+func TestVerify_ReplayOtherReplica(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 2, 3, 12, 0, 0, 0, time.UTC)
+	device := &types.Device{
+		Id:     uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+		Secret: "device-secret",
+	}
+
+	tests := []struct {
+		name    string
+		claimed bool
+		err     error
+	}{
+		{"claimed by other replica", false, nil},
+		{"database error", false, errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Define mocked executions.
+			devices := service.NewMockDeviceService(t)
+			devices.EXPECT().GetActive(mock.Anything, device.Id).Return(device, nil)
+			devices.EXPECT().ClaimNonce(mock.Anything, device.Id, "n1", mock.Anything, now).Return(tt.claimed, tt.err)
+
+			// Nonce is not in the cache of this replica.
+			v := &verifier{
+				devices: devices,
+				replay:  newReplayCache(),
+				maxSkew: 5 * time.Minute,
+				now:     func() time.Time { return now },
+			}
+
+			ctx := signedContext(t, http.MethodPost, "/api/v1/toll-events", "{}")
+			header := Sign(device.Id, device.Secret, http.MethodPost, "/api/v1/toll-events", []byte("{}"), now, "n1")
+
+			ret, err := v.verify(ctx, header)
+
+			test.Match(t, ret, errorReason(err))
+		})
+	}
+}
+
+func TestVerify_RetryAfterDatabaseError(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 2, 3, 12, 0, 0, 0, time.UTC)
+	device := &types.Device{
+		Id:     uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+		Secret: "device-secret",
+	}
+
+	// Define mocked executions.
+	devices := service.NewMockDeviceService(t)
+	devices.EXPECT().GetActive(mock.Anything, device.Id).Return(device, nil)
+	devices.EXPECT().ClaimNonce(mock.Anything, device.Id, "n1", mock.Anything, now).Return(false, errors.New("connection refused")).Once()
+	devices.EXPECT().ClaimNonce(mock.Anything, device.Id, "n1", mock.Anything, now).Return(true, nil).Once()
+
+	v := &verifier{
+		devices: devices,
+		replay:  newReplayCache(),
+		maxSkew: 5 * time.Minute,
+		now:     func() time.Time { return now },
+	}
+
+	ctx := signedContext(t, http.MethodPost, "/api/v1/toll-events", "{}")
+	header := Sign(device.Id, device.Secret, http.MethodPost, "/api/v1/toll-events", []byte("{}"), now, "n1")
+
+	_, errFirst := v.verify(ctx, header)
+	ret, errRetry := v.verify(ctx, header)
+
+	test.Match(t, errorReason(errFirst), ret, errorReason(errRetry))
+}
+
+func TestVerify_NoSecret(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 2, 3, 12, 0, 0, 0, time.UTC)
+	device := &types.Device{
+		Id: uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+	}
+
+	// Define mocked executions.
+	devices := service.NewMockDeviceService(t)
+	devices.EXPECT().GetActive(mock.Anything, device.Id).Return(device, nil)
+
+	v := &verifier{
+		devices: devices,
+		replay:  newReplayCache(),
+		maxSkew: 5 * time.Minute,
+		now:     func() time.Time { return now },
+	}
+
+	ctx := signedContext(t, http.MethodPost, "/api/v1/toll-events", "{}")
+	header := Sign(device.Id, "", http.MethodPost, "/api/v1/toll-events", []byte("{}"), now, "n1")
+
+	ret, err := v.verify(ctx, header)
+
+	test.Match(t, ret, errorReason(err))
+}
+
+func TestHandleHmacAuth(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	device := &types.Device{
+		Id:     uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+		Secret: "device-secret",
+		Scopes: types.Scopes{types.ScopeEventsWrite, types.ScopeKeysAdmin},
+	}
+
+	tests := []struct {
+		name      string
+		operation string
+		at        time.Time
+	}{
+		{"granted", restapi.RecordTollEventOperation, now},
+		{"missing scope", restapi.GetVehicleFeesOperation, now},
+		{"admin", restapi.ListApiKeysOperation, now},
+		{"stale", restapi.RecordTollEventOperation, now.Add(-time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Define mocked executions.
+			devices := service.NewMockDeviceService(t)
+			devices.EXPECT().GetActive(mock.Anything, device.Id).Return(device, nil).Maybe()
+			devices.EXPECT().ClaimNonce(mock.Anything, device.Id, "n1", mock.Anything, mock.Anything).Return(true, nil).Maybe()
+
+			op := &authorizer{
+				verifier: &verifier{
+					devices: devices,
+					replay:  newReplayCache(),
+					maxSkew: 5 * time.Minute,
+					now:     time.Now,
+				},
+				log: log.Noop(),
+			}
+
+			ctx := signedContext(t, http.MethodPost, "/api/v1/toll-events", "{}")
+			header := Sign(device.Id, device.Secret, http.MethodPost, "/api/v1/toll-events", []byte("{}"), tt.at, "n1")
+
+			ctx, err := op.HandleHmacAuth(ctx, tt.operation, restapi.HmacAuth{APIKey: header})
+
+			var apiErr *apierr.APIError
+			errors.As(err, &apiErr)
+
+			test.Match(t, identity.Get(ctx), apiErr)
+		})
+	}
+}
+
+// errorReason returns the message of the error without its trace.
+func errorReason(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,156 @@
+// Package calendar computes Swedish public holidays and toll-free dates for any year.
+package calendar
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+type (
+	// Holiday is a public holiday on a specific date.
+	Holiday struct {
+		Date time.Time
+		Name string
+	}
+
+	// Calendar holds computed holidays per year and additional exemption days.
+	Calendar struct {
+		mu         sync.RWMutex
+		years      map[int]map[string]string
+		exemptions map[string]struct{}
+	}
+)
+
+// New func returns Calendar with optional additional (e.g. municipal) exemption days.
+func New(exemptions ...time.Time) *Calendar {
+	c := &Calendar{
+		years: make(map[int]map[string]string),
+	}
+
+	c.SetExemptions(exemptions...)
+
+	return c
+}
+
+// SetExemptions replaces additional exemption days.
+func (c *Calendar) SetExemptions(exemptions ...time.Time) {
+	days := make(map[string]struct{}, len(exemptions))
+	for _, d := range exemptions {
+		days[d.Format(dateLayout)] = struct{}{}
+	}
+
+	c.mu.Lock()
+	c.exemptions = days
+	c.mu.Unlock()
+}
+
+// IsHoliday checks if the calendar date of t is a public holiday.
+func (c *Calendar) IsHoliday(t time.Time) bool {
+	_, ok := c.holidays(t.Year())[t.Format(dateLayout)]
+
+	return ok
+}
+
+// IsExemption checks if the calendar date of t is an additional exemption day.
+func (c *Calendar) IsExemption(t time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.exemptions[t.Format(dateLayout)]
+
+	return ok
+}
+
+// holidays returns cached holidays for the year keyed by date.
+func (c *Calendar) holidays(year int) map[string]string {
+	c.mu.RLock()
+	days, ok := c.years[year]
+	c.mu.RUnlock()
+
+	if ok {
+		return days
+	}
+
+	days = make(map[string]string)
+	for _, h := range Holidays(year) {
+		days[h.Date.Format(dateLayout)] = h.Name
+	}
+
+	c.mu.Lock()
+	c.years[year] = days
+	c.mu.Unlock()
+
+	return days
+}
+
+// Holidays returns Swedish public holidays for the year ordered by date.
+// Eves treated as holidays (Midsummer, Christmas and New Year's Eve) are included.
+func Holidays(year int) []Holiday {
+	easter := Easter(year)
+
+	ret := []Holiday{
+		{date(year, time.January, 1), "New Year's Day"},
+		{date(year, time.January, 6), "Epiphany"},
+		{easter.AddDate(0, 0, -2), "Good Friday"},
+		{easter.AddDate(0, 0, -1), "Easter Eve"},
+		{easter, "Easter Sunday"},
+		{easter.AddDate(0, 0, 1), "Easter Monday"},
+		{date(year, time.May, 1), "May Day"},
+		{easter.AddDate(0, 0, 39), "Ascension Day"},
+		{easter.AddDate(0, 0, 48), "Whitsun Eve"},
+		{easter.AddDate(0, 0, 49), "Whitsunday"},
+		{date(year, time.June, 6), "National Day"},
+		{weekdayFrom(date(year, time.June, 19), time.Friday), "Midsummer Eve"},
+		{weekdayFrom(date(year, time.June, 20), time.Saturday), "Midsummer Day"},
+		{weekdayFrom(date(year, time.October, 31), time.Saturday), "All Saints' Day"},
+		{date(year, time.December, 24), "Christmas Eve"},
+		{date(year, time.December, 25), "Christmas Day"},
+		{date(year, time.December, 26), "Boxing Day"},
+		{date(year, time.December, 31), "New Year's Eve"},
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Date.Before(ret[j].Date)
+	})
+
+	return ret
+}
+
+// Easter returns Easter Sunday for the year using the anonymous Gregorian algorithm.
+func Easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+
+	return date(year, time.Month(month), day)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// weekdayFrom returns first date on or after from that falls on the weekday.
+func weekdayFrom(from time.Time, weekday time.Weekday) time.Time {
+	offset := (int(weekday) - int(from.Weekday()) + 7) % 7
+
+	return from.AddDate(0, 0, offset)
+}
+
+// nextDay returns following calendar date of t in the same location.
+func nextDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+}
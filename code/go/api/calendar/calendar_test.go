@@ -0,0 +1,64 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"toll/internal/test"
+)
+
+func TestHolidays(t *testing.T) {
+	t.Parallel()
+
+	for _, year := range []int{2024, 2025, 2026} {
+		t.Run(time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC).Format("2006"), func(t *testing.T) {
+			t.Parallel()
+
+			days := []string{}
+			for _, h := range Holidays(year) {
+				days = append(days, h.Date.Format("2006-01-02 Mon")+" "+h.Name)
+			}
+
+			test.Match(t, days)
+		})
+	}
+}
+
+func TestDefaultPolicy(t *testing.T) {
+	t.Parallel()
+
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy := DefaultPolicy(New(time.Date(2025, time.September, 12, 0, 0, 0, 0, time.UTC)))
+
+	tests := []struct {
+		name string
+		date time.Time
+	}{
+		{"regular weekday", time.Date(2025, time.February, 3, 12, 0, 0, 0, time.UTC)},
+		{"saturday", time.Date(2025, time.February, 1, 12, 0, 0, 0, time.UTC)},
+		{"sunday", time.Date(2025, time.February, 2, 12, 0, 0, 0, time.UTC)},
+		{"new year's day", time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)},
+		{"day before epiphany", time.Date(2025, time.January, 5, 12, 0, 0, 0, time.UTC)},
+		{"good friday", time.Date(2025, time.April, 18, 12, 0, 0, 0, time.UTC)},
+		{"day before good friday", time.Date(2025, time.April, 17, 12, 0, 0, 0, time.UTC)},
+		{"ascension day", time.Date(2025, time.May, 29, 12, 0, 0, 0, time.UTC)},
+		{"midsummer eve", time.Date(2025, time.June, 20, 12, 0, 0, 0, time.UTC)},
+		{"july", time.Date(2025, time.July, 15, 12, 0, 0, 0, time.UTC)},
+		{"all saints' day", time.Date(2025, time.November, 1, 12, 0, 0, 0, time.UTC)},
+		{"exemption day", time.Date(2025, time.September, 12, 12, 0, 0, 0, time.UTC)},
+		{"local new year's day from utc", time.Date(2024, time.December, 31, 23, 30, 0, 0, time.UTC).In(stockholm)},
+		{"local day after new year's day", time.Date(2025, time.January, 1, 23, 30, 0, 0, time.UTC).In(stockholm)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			test.Match(t, tt.date.Format(time.RFC3339), policy.IsExempt(tt.date))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package calendar
+
+import (
+	"time"
+)
+
+type (
+	// DateExemptionPolicy decides if the calendar date of t is toll-free.
+	DateExemptionPolicy interface {
+		IsExempt(t time.Time) bool
+	}
+
+	// PolicyFunc adapts a function to DateExemptionPolicy.
+	PolicyFunc func(t time.Time) bool
+)
+
+// IsExempt calls f(t).
+func (f PolicyFunc) IsExempt(t time.Time) bool {
+	return f(t)
+}
+
+// DefaultPolicy returns Swedish toll rules: weekends, public holidays and
+// exemption days, days before public holidays and the whole of July are toll-free.
+func DefaultPolicy(c *Calendar) DateExemptionPolicy {
+	return AnyPolicy(
+		WeekendPolicy(),
+		HolidayPolicy(c),
+		EveOfHolidayPolicy(c),
+		MonthPolicy(time.July),
+	)
+}
+
+// AnyPolicy returns policy exempting dates exempted by at least one of the policies.
+func AnyPolicy(policies ...DateExemptionPolicy) DateExemptionPolicy {
+	return PolicyFunc(func(t time.Time) bool {
+		for _, p := range policies {
+			if p.IsExempt(t) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// WeekendPolicy returns policy exempting Saturdays and Sundays.
+func WeekendPolicy() DateExemptionPolicy {
+	return PolicyFunc(func(t time.Time) bool {
+		weekday := t.Weekday()
+
+		return weekday == time.Saturday || weekday == time.Sunday
+	})
+}
+
+// HolidayPolicy returns policy exempting public holidays and additional exemption days.
+func HolidayPolicy(c *Calendar) DateExemptionPolicy {
+	return PolicyFunc(func(t time.Time) bool {
+		return c.IsHoliday(t) || c.IsExemption(t)
+	})
+}
+
+// EveOfHolidayPolicy returns policy exempting days before public holidays.
+func EveOfHolidayPolicy(c *Calendar) DateExemptionPolicy {
+	return PolicyFunc(func(t time.Time) bool {
+		return c.IsHoliday(nextDay(t))
+	})
+}
+
+// MonthPolicy returns policy exempting all days of the month.
+func MonthPolicy(month time.Month) DateExemptionPolicy {
+	return PolicyFunc(func(t time.Time) bool {
+		return t.Month() == month
+	})
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"time"
+
+	"github.com/jnovack/flag"
+
+	"toll/internal/errlog"
+)
+
+type (
+	ApiKeyCache struct {
+		TTL         time.Duration
+		NegativeTTL time.Duration
+		Size        int
+	}
+)
+
+var apiKeyCacheFlags *ApiKeyCache
+
+func (c *ApiKeyCache) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.TTL < 0 || c.NegativeTTL < 0 {
+		return errlog.New("api key cache ttl should not be negative")
+	}
+
+	if c.Size <= 0 {
+		return errlog.New("api key cache size should be positive")
+	}
+
+	return nil
+}
+
+func (*ApiKeyCache) Init(prefix ...string) *ApiKeyCache {
+	if apiKeyCacheFlags != nil {
+		return apiKeyCacheFlags
+	}
+
+	p := func(s string) string {
+		return prefix[0] + "_" + s
+	}
+
+	apiKeyCacheFlags = new(ApiKeyCache)
+
+	flag.DurationVar(
+		&apiKeyCacheFlags.TTL,
+		p("api_key_cache_ttl"),
+		time.Minute,
+		"How long validated api keys are cached, 0 disables the cache",
+	)
+
+	flag.DurationVar(
+		&apiKeyCacheFlags.NegativeTTL,
+		p("api_key_cache_negative_ttl"),
+		5*time.Second,
+		"How long unknown api keys are cached, 0 disables negative caching",
+	)
+
+	flag.IntVar(
+		&apiKeyCacheFlags.Size,
+		p("api_key_cache_size"),
+		10000,
+		"Maximum number of cached api keys",
+	)
+
+	return apiKeyCacheFlags
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"time"
+
+	"github.com/jnovack/flag"
+
+	"toll/internal/errlog"
+)
+
+type (
+	Billing struct {
+		Timezone     string
+		DrainTimeout time.Duration
+		MaxBacklog   int
+		Rebill       string
+
+		location  *time.Location
+		rebillDay time.Time
+	}
+)
+
+var billing *Billing
+
+func (c *Billing) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.Timezone == "" {
+		return errlog.New("no billing timezone is set")
+	}
+
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return errlog.Errorf("invalid billing timezone %q: %w", c.Timezone, err)
+	}
+
+	if c.DrainTimeout <= 0 {
+		return errlog.New("billing drain timeout should be positive")
+	}
+
+	if c.MaxBacklog < 0 {
+		return errlog.New("billing max backlog should not be negative")
+	}
+
+	if c.Rebill != "" {
+		day, err := time.ParseInLocation(time.DateOnly, c.Rebill, loc)
+		if err != nil {
+			return errlog.Errorf("invalid billing rebill day %q: %w", c.Rebill, err)
+		}
+
+		c.rebillDay = day
+	}
+
+	c.location = loc
+
+	return nil
+}
+
+// RebillDay returns billing day to recompute instead of starting the API, if any.
+func (c *Billing) RebillDay() (time.Time, bool) {
+	if c == nil || c.rebillDay.IsZero() {
+		return time.Time{}, false
+	}
+
+	return c.rebillDay, true
+}
+
+// Location returns billing timezone location, UTC when flags are not validated.
+func (c *Billing) Location() *time.Location {
+	if c == nil || c.location == nil {
+		return time.UTC
+	}
+
+	return c.location
+}
+
+func (*Billing) Init(prefix ...string) *Billing {
+	if billing != nil {
+		return billing
+	}
+
+	p := func(s string) string {
+		return prefix[0] + "_" + s
+	}
+
+	billing = new(Billing)
+
+	flag.StringVar(
+		&billing.Timezone,
+		p("billing_timezone"),
+		"Europe/Stockholm",
+		"Timezone used for billing days, fee windows and holidays",
+	)
+
+	flag.DurationVar(
+		&billing.DrainTimeout,
+		p("billing_drain_timeout"),
+		30*time.Second,
+		"Time to wait for billing workers to finish in-flight jobs on shutdown",
+	)
+
+	flag.IntVar(
+		&billing.MaxBacklog,
+		p("billing_max_backlog"),
+		100000,
+		"Pending billing jobs above which toll events are rejected with 503, 0 disables the limit",
+	)
+
+	flag.StringVar(
+		&billing.Rebill,
+		p("billing_rebill"),
+		"",
+		"Billing day (YYYY-MM-DD) to enqueue recomputing daily fees for, the API exits after enqueueing instead of serving",
+	)
+
+	return billing
+}
@@ -8,9 +8,10 @@
 
 type (
 	Service struct {
-		Addr   string
-		Domain string
-		Debug  bool
+		Addr        string
+		MetricsAddr string
+		Domain      string
+		Debug       bool
 	}
 )
 
@@ -25,6 +26,10 @@ func (c *Service) Validate() error {
 		return errlog.New("no service addr is set, can't listen for connections")
 	}
 
+	if c.MetricsAddr == c.Addr {
+		return errlog.New("metrics addr must differ from service addr, metrics are internal")
+	}
+
 	if c.Domain == "" {
 		return errlog.New("no domain is set")
 	}
@@ -50,6 +55,13 @@ func (*Service) Init(prefix ...string) *Service {
 		"Listen address for server",
 	)
 
+	flag.StringVar(
+		&svc.MetricsAddr,
+		p("metrics_addr"),
+		":9090",
+		"Internal listen address for Prometheus metrics, empty disables metrics endpoint",
+	)
+
 	flag.BoolVar(
 		&svc.Debug,
 		p("debug"),
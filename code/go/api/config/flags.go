@@ -4,7 +4,12 @@
 
 type (
 	AppFlags struct {
-		Svc *Service
+		Svc         *Service
+		Billing     *Billing
+		Validation  *Validation
+		ApiKeyCache *ApiKeyCache
+		Signing     *Signing
+		TLS         *TLS
 	}
 )
 
@@ -23,6 +28,26 @@ func (c *AppFlags) Validate() error {
 		return err
 	}
 
+	if err := c.Billing.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Validation.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.ApiKeyCache.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Signing.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.TLS.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -37,5 +62,10 @@ func Flags(prefix ...string) {
 
 	flags = &AppFlags{
 		new(Service).Init(prefix...),
+		new(Billing).Init(prefix...),
+		new(Validation).Init(prefix...),
+		new(ApiKeyCache).Init(prefix...),
+		new(Signing).Init(prefix...),
+		new(TLS).Init(prefix...),
 	}
 }
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"time"
+
+	"github.com/jnovack/flag"
+
+	"toll/internal/errlog"
+)
+
+type (
+	Signing struct {
+		MaxSkew time.Duration
+	}
+)
+
+var signingFlags *Signing
+
+func (c *Signing) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.MaxSkew <= 0 {
+		return errlog.New("signature max skew should be positive")
+	}
+
+	return nil
+}
+
+func (*Signing) Init(prefix ...string) *Signing {
+	if signingFlags != nil {
+		return signingFlags
+	}
+
+	p := func(s string) string {
+		return prefix[0] + "_" + s
+	}
+
+	signingFlags = new(Signing)
+
+	flag.DurationVar(
+		&signingFlags.MaxSkew,
+		p("signature_max_skew"),
+		5*time.Minute,
+		"Accepted difference between signed request timestamp and server clock",
+	)
+
+	return signingFlags
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"crypto/x509"
+	"os"
+
+	"github.com/jnovack/flag"
+
+	"toll/internal/errlog"
+)
+
+type (
+	TLS struct {
+		Addr         string
+		CertFile     string
+		KeyFile      string
+		ClientCAFile string
+	}
+)
+
+var tlsFlags *TLS
+
+func (c *TLS) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+
+	if c.CertFile == "" || c.KeyFile == "" {
+		return errlog.New("TLS listener needs certificate and key files")
+	}
+
+	if c.ClientCAFile == "" {
+		return errlog.New("TLS listener needs client CA file to verify devices")
+	}
+
+	return nil
+}
+
+// Enabled checks if the mutual TLS listener is configured.
+func (c *TLS) Enabled() bool {
+	return c != nil && c.Addr != ""
+}
+
+// ClientCAs returns pool of CAs which sign device certificates.
+func (c *TLS) ClientCAs() (*x509.CertPool, error) {
+	pem, err := os.ReadFile(c.ClientCAFile)
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errlog.Errorf("no certificates found in %s", c.ClientCAFile)
+	}
+
+	return pool, nil
+}
+
+func (*TLS) Init(prefix ...string) *TLS {
+	if tlsFlags != nil {
+		return tlsFlags
+	}
+
+	p := func(s string) string {
+		return prefix[0] + "_" + s
+	}
+
+	tlsFlags = new(TLS)
+
+	flag.StringVar(
+		&tlsFlags.Addr,
+		p("tls_addr"),
+		"",
+		"Listen address for mutual TLS device connections, empty disables the listener",
+	)
+
+	flag.StringVar(
+		&tlsFlags.CertFile,
+		p("tls_cert_file"),
+		"",
+		"Server certificate PEM file of the TLS listener",
+	)
+
+	flag.StringVar(
+		&tlsFlags.KeyFile,
+		p("tls_key_file"),
+		"",
+		"Server private key PEM file of the TLS listener",
+	)
+
+	flag.StringVar(
+		&tlsFlags.ClientCAFile,
+		p("tls_client_ca_file"),
+		"",
+		"PEM file of CAs signing device client certificates",
+	)
+
+	return tlsFlags
+}
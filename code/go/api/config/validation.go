@@ -0,0 +1,115 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jnovack/flag"
+
+	"toll/api/validation"
+	"toll/internal/errlog"
+)
+
+type (
+	Validation struct {
+		PlateCountries string
+		MaxEventAge    time.Duration
+		ClockSkew      time.Duration
+		MaxStay        time.Duration
+
+		countries []string
+	}
+)
+
+var validationFlags *Validation
+
+func (c *Validation) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	c.countries = nil
+
+	for _, country := range strings.Split(c.PlateCountries, ",") {
+		country = strings.ToUpper(strings.TrimSpace(country))
+		if country == "" {
+			continue
+		}
+
+		if !validation.IsKnownCountry(country) {
+			return errlog.Errorf("no plate format for country %q", country)
+		}
+
+		c.countries = append(c.countries, country)
+	}
+
+	if c.MaxEventAge < 0 {
+		return errlog.New("max event age should not be negative")
+	}
+
+	if c.ClockSkew < 0 {
+		return errlog.New("clock skew should not be negative")
+	}
+
+	if c.MaxStay <= 0 {
+		return errlog.New("max stay should be positive")
+	}
+
+	return nil
+}
+
+// Rules returns toll event validation rules.
+func (c *Validation) Rules() validation.Rules {
+	if c == nil {
+		return validation.Rules{}
+	}
+
+	return validation.Rules{
+		Countries:   c.countries,
+		MaxEventAge: c.MaxEventAge,
+		ClockSkew:   c.ClockSkew,
+		MaxStay:     c.MaxStay,
+	}
+}
+
+func (*Validation) Init(prefix ...string) *Validation {
+	if validationFlags != nil {
+		return validationFlags
+	}
+
+	p := func(s string) string {
+		return prefix[0] + "_" + s
+	}
+
+	validationFlags = new(Validation)
+
+	flag.StringVar(
+		&validationFlags.PlateCountries,
+		p("validation_plate_countries"),
+		"SE,NO,DK,FI,DE,FR",
+		"Comma separated country codes of accepted plate formats, empty accepts any plate",
+	)
+
+	flag.DurationVar(
+		&validationFlags.MaxEventAge,
+		p("validation_max_event_age"),
+		30*24*time.Hour,
+		"Oldest toll event start accepted, 0 disables the limit",
+	)
+
+	flag.DurationVar(
+		&validationFlags.ClockSkew,
+		p("validation_clock_skew"),
+		30*time.Second,
+		"Tolerated difference between gantry and server clocks",
+	)
+
+	flag.DurationVar(
+		&validationFlags.MaxStay,
+		p("validation_max_stay"),
+		4*time.Hour,
+		"Longest accepted time between toll event start and stop, also caps stays in billing",
+	)
+
+	return validationFlags
+}
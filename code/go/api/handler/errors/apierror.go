@@ -9,7 +9,6 @@ func NewAPIError(code int, message string) *APIError {
 	return &APIError{
 		StatusCode: code,
 		Title:      message,
-		ErrorCode:  code,
 	}
 }
 
@@ -19,6 +18,14 @@ type APIError struct {
 	Detail     string
 	StatusCode int
 	ErrorCode  int
+
+	InvalidParams []InvalidParam
+}
+
+// InvalidParam describes request field that failed validation.
+type InvalidParam struct {
+	Name   string
+	Reason string
 }
 
 // WithDetails set title of the error.
@@ -28,6 +35,8 @@ func (a *APIError) WithTitle(title string) *APIError {
 		Title:      title,
 		Detail:     a.Detail,
 		ErrorCode:  a.ErrorCode,
+
+		InvalidParams: a.InvalidParams,
 	}
 }
 
@@ -38,6 +47,8 @@ func (a *APIError) WithDetails(details string, args ...interface{}) *APIError {
 		Title:      a.Title,
 		Detail:     fmt.Sprintf(details, args...),
 		ErrorCode:  a.ErrorCode,
+
+		InvalidParams: a.InvalidParams,
 	}
 }
 
@@ -48,6 +59,20 @@ func (a *APIError) WithErrorCode(errorCode int) *APIError {
 		Title:      a.Title,
 		Detail:     a.Detail,
 		ErrorCode:  errorCode,
+
+		InvalidParams: a.InvalidParams,
+	}
+}
+
+// WithInvalidParams set request fields that failed validation.
+func (a *APIError) WithInvalidParams(params ...InvalidParam) *APIError {
+	return &APIError{
+		StatusCode: a.StatusCode,
+		Title:      a.Title,
+		Detail:     a.Detail,
+		ErrorCode:  a.ErrorCode,
+
+		InvalidParams: params,
 	}
 }
 
@@ -56,10 +81,41 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("ErrorCode: %d: Title: %s, Detail: %s, ErrorCode: %d", e.ErrorCode, e.Title, e.Detail, e.ErrorCode)
 }
 
+// Code returns error code name from the catalogue, the one of the status code
+// when error code is not set.
+func (e *APIError) Code() string {
+	if name, ok := codeNames[e.ErrorCode]; ok {
+		return name
+	}
+
+	if name, ok := codeNames[codeOfStatus(e.StatusCode)]; ok {
+		return name
+	}
+
+	return codeNames[CodeInternal]
+}
+
+// Type returns URI of the problem type.
+func (e *APIError) Type() string {
+	return ProblemTypePrefix + e.Code()
+}
+
 var (
-	ErrAPIUnauthorized = NewAPIError(http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized))
-	ErrAPIForbidden    = NewAPIError(http.StatusForbidden, http.StatusText(http.StatusForbidden))
-	ErrAPIBadRequest   = NewAPIError(http.StatusBadRequest, http.StatusText(http.StatusBadRequest))
-	ErrAPINotFound     = NewAPIError(http.StatusNotFound, http.StatusText(http.StatusNotFound))
-	ErrAPIInternal     = NewAPIError(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	ErrAPIUnauthorized         = newAPIError(http.StatusUnauthorized, CodeUnauthorized)
+	ErrAPIForbidden            = newAPIError(http.StatusForbidden, CodeForbidden)
+	ErrAPIBadRequest           = newAPIError(http.StatusBadRequest, CodeBadRequest)
+	ErrAPIInvalidRequest       = newAPIError(http.StatusBadRequest, CodeInvalidRequest)
+	ErrAPIValidation           = newAPIError(http.StatusBadRequest, CodeValidationFailed)
+	ErrAPINotFound             = newAPIError(http.StatusNotFound, CodeNotFound)
+	ErrAPIMethodNotAllowed     = newAPIError(http.StatusMethodNotAllowed, CodeMethodNotAllowed)
+	ErrAPITooManyRequests      = newAPIError(http.StatusTooManyRequests, CodeTooManyRequests)
+	ErrAPIIdempotencyKeyReused = newAPIError(http.StatusUnprocessableEntity, CodeIdempotencyKeyReused)
+	ErrAPIInternal             = newAPIError(http.StatusInternalServerError, CodeInternal)
+	ErrAPINotImplemented       = newAPIError(http.StatusNotImplemented, CodeNotImplemented)
+	ErrAPIUnavailable          = newAPIError(http.StatusServiceUnavailable, CodeServiceUnavailable)
 )
+
+// newAPIError returns API error with status text title and catalogue error code.
+func newAPIError(status, errorCode int) *APIError {
+	return NewAPIError(status, http.StatusText(status)).WithErrorCode(errorCode)
+}
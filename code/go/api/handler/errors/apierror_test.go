@@ -53,3 +53,24 @@ func TestErrorCode(t *testing.T) {
 	assert.Equal(t, "", err.Detail)
 	assert.Equal(t, 99, err.ErrorCode)
 }
+
+func TestWithInvalidParams(t *testing.T) {
+	t.Parallel()
+
+	err := NewAPIError(400, "bad request")
+	err = err.WithInvalidParams(InvalidParam{Name: "license_plate", Reason: "license plate is required"})
+	err = err.WithDetails("toll event is invalid")
+
+	assert.Equal(t, 400, err.StatusCode)
+	assert.Equal(t, "toll event is invalid", err.Detail)
+	assert.Equal(t, []InvalidParam{{Name: "license_plate", Reason: "license plate is required"}}, err.InvalidParams)
+}
+
+func TestCode(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "validation_failed", ErrAPIValidation.Code())
+	assert.Equal(t, "urn:toll:problem:validation_failed", ErrAPIValidation.Type())
+	assert.Equal(t, "too_many_requests", NewAPIError(429, "slow down").Code())
+	assert.Equal(t, "internal_error", NewAPIError(500, "internal").WithErrorCode(99).Code())
+}
@@ -0,0 +1,62 @@
+package errors
+
+import "net/http"
+
+// ProblemTypePrefix prefixes error code in problem type URIs.
+const ProblemTypePrefix = "urn:toll:problem:"
+
+// Error codes returned as problem error_code. Codes are part of the API, they
+// are never renumbered or reused for a different problem.
+const (
+	CodeBadRequest           = 1000
+	CodeInvalidRequest       = 1001
+	CodeValidationFailed     = 1002
+	CodeUnauthorized         = 1100
+	CodeForbidden            = 1101
+	CodeNotFound             = 1200
+	CodeMethodNotAllowed     = 1201
+	CodeTooManyRequests      = 1300
+	CodeIdempotencyKeyReused = 1400
+	CodeInternal             = 2000
+	CodeNotImplemented       = 2001
+	CodeServiceUnavailable   = 2002
+)
+
+var codeNames = map[int]string{
+	CodeBadRequest:           "bad_request",
+	CodeInvalidRequest:       "invalid_request",
+	CodeValidationFailed:     "validation_failed",
+	CodeUnauthorized:         "unauthorized",
+	CodeForbidden:            "forbidden",
+	CodeNotFound:             "not_found",
+	CodeMethodNotAllowed:     "method_not_allowed",
+	CodeTooManyRequests:      "too_many_requests",
+	CodeIdempotencyKeyReused: "idempotency_key_reused",
+	CodeInternal:             "internal_error",
+	CodeNotImplemented:       "not_implemented",
+	CodeServiceUnavailable:   "service_unavailable",
+}
+
+// codeOfStatus returns generic error code of the HTTP status.
+func codeOfStatus(status int) int {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case http.StatusNotImplemented:
+		return CodeNotImplemented
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	default:
+		return CodeInternal
+	}
+}
@@ -2,15 +2,13 @@
 
 import (
 	"context"
-	"fmt"
-	"net/http"
-
-	"github.com/ogen-go/ogen/ogenerrors"
 
+	"toll/api/config"
 	apiErrors "toll/api/handler/errors"
 	"toll/api/restapi"
 	"toll/api/service"
 	"toll/api/types"
+	"toll/api/validation"
 
 	log "toll/internal/log"
 )
@@ -20,6 +18,10 @@ type apiService struct {
 
 	tollEvents service.TollEventService
 	billing    service.BillingService
+	dailyFees  service.DailyFeeService
+	apiKeys    service.ApiKeyService
+
+	validator *validation.Validator
 }
 
 func NewApiHandlers() *apiService {
@@ -28,6 +30,10 @@ func NewApiHandlers() *apiService {
 
 		tollEvents: service.TollEvents,
 		billing:    service.Billing,
+		dailyFees:  service.DailyFees,
+		apiKeys:    service.ApiKeys,
+
+		validator: validation.New(config.Get().Validation.Rules()),
 	}
 }
 
@@ -35,33 +41,17 @@ func (s *apiService) Health(ctx context.Context) error {
 	return nil
 }
 
+// NewError maps errors returned by handlers and security handlers to problem response.
 func (s *apiService) NewError(ctx context.Context, err error) *restapi.ProblemStatusCode {
-	//nolint:all
-	switch apiErr := err.(type) {
-	case *apiErrors.APIError:
-		return &restapi.ProblemStatusCode{
-			StatusCode: apiErr.StatusCode,
-			Response: restapi.Problem{
-				Status:    restapi.NewOptInt32(int32(apiErr.StatusCode)),
-				Title:     restapi.NewOptString(apiErr.Title),
-				Detail:    restapi.NewOptString(apiErr.Detail),
-				ErrorCode: restapi.NewOptString(fmt.Sprint(apiErr.ErrorCode)),
-			},
-		}
-	case *ogenerrors.SecurityError:
-		return &restapi.ProblemStatusCode{
-			StatusCode: http.StatusForbidden,
-			Response: restapi.Problem{
-				Title: restapi.NewOptString(http.StatusText(http.StatusForbidden)),
-			},
-		}
-	default:
-		return &restapi.ProblemStatusCode{
-			StatusCode: http.StatusInternalServerError,
-			Response: restapi.Problem{
-				Status: restapi.NewOptInt32(http.StatusInternalServerError),
-				Title:  restapi.NewOptString(http.StatusText(http.StatusInternalServerError)),
-			},
-		}
+	apiErr := apiError(err)
+	if apiErr == nil {
+		s.log.Errore(err)
+
+		apiErr = apiErrors.ErrAPIInternal
+	}
+
+	return &restapi.ProblemStatusCode{
+		StatusCode: apiErr.StatusCode,
+		Response:   problem(ctx, apiErr),
 	}
 }
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	apiErrors "toll/api/handler/errors"
+	"toll/api/mapper"
+	"toll/api/service"
+	"toll/api/types"
+
+	"toll/api/identity"
+	"toll/api/restapi"
+)
+
+func (s *apiService) ListApiKeys(ctx context.Context) (restapi.ListApiKeysRes, error) {
+	principal := identity.Get(ctx)
+	if principal == nil {
+		return nil, apiErrors.ErrAPIUnauthorized
+	}
+
+	keys, err := s.apiKeys.List(ctx)
+	if err != nil {
+		s.log.Errore(err)
+
+		return nil, apiErrors.ErrAPIInternal
+	}
+
+	return &restapi.ApiKeyList{Items: mapper.ApiKeysToModel(keys)}, nil
+}
+
+func (s *apiService) CreateApiKey(ctx context.Context, req *restapi.ApiKeyCreate) (restapi.CreateApiKeyRes, error) {
+	principal := identity.Get(ctx)
+	if principal == nil {
+		return nil, apiErrors.ErrAPIUnauthorized
+	}
+
+	key := mapper.ModelToApiKey(req)
+	if key.Organisation == "" {
+		key.Organisation = principal.Organisation
+	}
+
+	var invalid []apiErrors.InvalidParam
+
+	if !key.ExpiresAt.After(time.Now()) {
+		invalid = append(invalid, apiErrors.InvalidParam{Name: "expires_at", Reason: "should be in the future"})
+	}
+
+	if slices.Contains(key.Scopes, types.ScopeKeysAdmin) && !key.SystemKey {
+		invalid = append(invalid, apiErrors.InvalidParam{Name: "scopes", Reason: "keys:admin scope needs a system key"})
+	}
+
+	if key.GantryId != nil && key.SystemKey {
+		invalid = append(invalid, apiErrors.InvalidParam{Name: "gantry_id", Reason: "gantry keys cannot be system keys"})
+	}
+
+	if len(invalid) > 0 {
+		return nil, apiErrors.ErrAPIValidation.
+			WithDetails("API key is invalid").
+			WithInvalidParams(invalid...)
+	}
+
+	plaintext, err := s.apiKeys.Create(ctx, key)
+	if err != nil {
+		s.log.Errore(err)
+
+		return nil, apiErrors.ErrAPIInternal
+	}
+
+	s.log.Infof("API key %s created by %s with scopes %v, system key: %t", key.Id, principal.KeyId, key.Scopes, key.SystemKey)
+
+	return &restapi.ApiKeyCreated{
+		APIKey: mapper.ApiKeyToModel(key),
+		Key:    plaintext,
+	}, nil
+}
+
+func (s *apiService) UpdateApiKey(
+	ctx context.Context,
+	req *restapi.ApiKeyUpdate,
+	params restapi.UpdateApiKeyParams,
+) (restapi.UpdateApiKeyRes, error) {
+	principal := identity.Get(ctx)
+	if principal == nil {
+		return nil, apiErrors.ErrAPIUnauthorized
+	}
+
+	key, err := s.apiKeys.SetExpiry(ctx, params.ID, req.ExpiresAt)
+	if errors.Is(err, service.ErrNotFound) {
+		return nil, apiErrors.ErrAPINotFound.
+			WithDetails("no API key %s", params.ID)
+	}
+
+	if err != nil {
+		s.log.Errore(err)
+
+		return nil, apiErrors.ErrAPIInternal
+	}
+
+	ret := mapper.ApiKeyToModel(key)
+
+	return &ret, nil
+}
+
+func (s *apiService) RevokeApiKey(ctx context.Context, params restapi.RevokeApiKeyParams) (restapi.RevokeApiKeyRes, error) {
+	principal := identity.Get(ctx)
+	if principal == nil {
+		return nil, apiErrors.ErrAPIUnauthorized
+	}
+
+	err := s.apiKeys.Revoke(ctx, params.ID)
+	if errors.Is(err, service.ErrNotFound) {
+		return nil, apiErrors.ErrAPINotFound.
+			WithDetails("no API key %s", params.ID)
+	}
+
+	if err != nil {
+		s.log.Errore(err)
+
+		return nil, apiErrors.ErrAPIInternal
+	}
+
+	s.log.Infof("API key %s revoked by %s", params.ID, principal.KeyId)
+
+	return &restapi.RevokeApiKeyNoContent{}, nil
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	apiErrors "toll/api/handler/errors"
+	"toll/api/mapper"
+	"toll/api/service"
+	"toll/api/types"
+	"toll/api/validation"
+
+	"toll/api/identity"
+	"toll/api/restapi"
+)
+
+func (s *apiService) GetVehicleFees(ctx context.Context, params restapi.GetVehicleFeesParams) (restapi.GetVehicleFeesRes, error) {
+	principal := identity.Get(ctx)
+	if principal == nil {
+		return nil, apiErrors.ErrAPIUnauthorized
+	}
+
+	filter := types.DailyFeeFilter{
+		Limit:  params.Limit.Or(100),
+		Offset: params.Offset.Or(0),
+	}
+
+	if from, ok := params.From.Get(); ok {
+		filter.From = &from
+	}
+
+	if to, ok := params.To.Get(); ok {
+		filter.To = &to
+	}
+
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, apiErrors.ErrAPIValidation.
+			WithDetails("fees range end should not be before its start").
+			WithInvalidParams(apiErrors.InvalidParam{Name: "to", Reason: "should not be before from"})
+	}
+
+	plate := validation.NormalizePlate(params.LicensePlate)
+
+	page, err := s.dailyFees.GetForLicense(ctx, plate, filter)
+	if errors.Is(err, service.ErrNotFound) {
+		return nil, apiErrors.ErrAPINotFound.
+			WithDetails("no fees found for license plate %s", plate)
+	}
+
+	if err != nil {
+		s.log.Errore(err)
+
+		return nil, apiErrors.ErrAPIInternal
+	}
+
+	ret := &restapi.DailyFeePage{
+		Items:  mapper.DailyFeesToModel(page.Fees),
+		Limit:  filter.Limit,
+		Offset: filter.Offset,
+	}
+
+	if page.HasMore {
+		ret.NextOffset = restapi.NewOptInt(filter.Offset + len(page.Fees))
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	ht "github.com/ogen-go/ogen/http"
+	"github.com/ogen-go/ogen/ogenerrors"
+	"github.com/ogen-go/ogen/validate"
+
+	apiErrors "toll/api/handler/errors"
+	"toll/api/restapi"
+
+	"toll/internal/request"
+)
+
+// ErrorHandler writes problem response for errors ogen does not pass to NewError,
+// e.g. request decoding errors.
+func (s *apiService) ErrorHandler(ctx context.Context, w http.ResponseWriter, _ *http.Request, err error) {
+	s.writeProblem(ctx, w, s.NewError(ctx, err))
+}
+
+// NotFound writes problem response for unknown paths.
+func (s *apiService) NotFound(w http.ResponseWriter, r *http.Request) {
+	s.writeProblem(r.Context(), w, s.NewError(r.Context(), apiErrors.ErrAPINotFound.
+		WithDetails("no operation at %s", r.URL.Path)))
+}
+
+// MethodNotAllowed writes problem response for known paths requested with unsupported method.
+func (s *apiService) MethodNotAllowed(w http.ResponseWriter, r *http.Request, allowed string) {
+	w.Header().Set("Allow", allowed)
+
+	s.writeProblem(r.Context(), w, s.NewError(r.Context(), apiErrors.ErrAPIMethodNotAllowed.
+		WithDetails("method %s is not allowed, allowed methods are %s", r.Method, allowed)))
+}
+
+func (s *apiService) writeProblem(ctx context.Context, w http.ResponseWriter, res *restapi.ProblemStatusCode) {
+	body, err := res.Response.MarshalJSON()
+	if err != nil {
+		s.log.Errore(err)
+
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(res.StatusCode)
+
+	if _, err = w.Write(body); err != nil {
+		s.log.Debugf("write problem response of request %s: %v", request.GetReqIdCtx(ctx), err)
+	}
+}
+
+// apiError maps handler, security and request decoding errors to API error.
+// Returns nil for unexpected errors.
+func apiError(err error) *apiErrors.APIError {
+	var apiErr *apiErrors.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	var securityErr *ogenerrors.SecurityError
+	if errors.As(err, &securityErr) {
+		return apiErrors.ErrAPIUnauthorized.WithDetails("missing or invalid API key")
+	}
+
+	if errors.Is(err, ht.ErrNotImplemented) {
+		return apiErrors.ErrAPINotImplemented
+	}
+
+	var (
+		paramsErr  *ogenerrors.DecodeParamsError
+		requestErr *ogenerrors.DecodeRequestError
+	)
+
+	if errors.As(err, &paramsErr) || errors.As(err, &requestErr) {
+		return apiErrors.ErrAPIInvalidRequest.
+			WithDetails("%s", decodeDetail(err)).
+			WithInvalidParams(decodeInvalidParams(err)...)
+	}
+
+	return nil
+}
+
+// decodeDetail returns decoding error message without the operation prefix.
+func decodeDetail(err error) string {
+	msg := err.Error()
+	if i := strings.Index(msg, ": "); i >= 0 && strings.HasPrefix(msg, "operation ") {
+		return msg[i+2:]
+	}
+
+	return msg
+}
+
+// decodeInvalidParams returns invalid fields of the decoding error, if known.
+func decodeInvalidParams(err error) []apiErrors.InvalidParam {
+	var paramErr *ogenerrors.DecodeParamError
+	if errors.As(err, &paramErr) {
+		return []apiErrors.InvalidParam{{Name: paramErr.Name, Reason: paramErr.Err.Error()}}
+	}
+
+	var validateErr *validate.Error
+	if errors.As(err, &validateErr) {
+		params := make([]apiErrors.InvalidParam, 0, len(validateErr.Fields))
+
+		for _, field := range validateErr.Fields {
+			params = append(params, apiErrors.InvalidParam{Name: field.Name, Reason: field.Error.Error()})
+		}
+
+		return params
+	}
+
+	return nil
+}
+
+// problem returns RFC 7807 problem of the API error for the request in context.
+func problem(ctx context.Context, apiErr *apiErrors.APIError) restapi.Problem {
+	ret := restapi.Problem{
+		Status:    restapi.NewOptInt32(int32(apiErr.StatusCode)),
+		Title:     restapi.NewOptString(apiErr.Title),
+		ErrorCode: restapi.NewOptString(apiErr.Code()),
+	}
+
+	if typ, err := url.Parse(apiErr.Type()); err == nil {
+		ret.Type = restapi.NewOptURI(*typ)
+	}
+
+	if apiErr.Detail != "" {
+		ret.Detail = restapi.NewOptString(apiErr.Detail)
+	}
+
+	if rid := request.GetReqIdCtx(ctx); rid != "" {
+		ret.Instance = restapi.NewOptURI(url.URL{Scheme: "urn", Opaque: "toll:request:" + url.PathEscape(rid)})
+	}
+
+	for _, param := range apiErr.InvalidParams {
+		ret.InvalidParams = append(ret.InvalidParams, restapi.InvalidParam{
+			Name:   param.Name,
+			Reason: param.Reason,
+		})
+	}
+
+	return ret
+}
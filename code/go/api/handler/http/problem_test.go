@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ogen-go/ogen/ogenerrors"
+	"github.com/ogen-go/ogen/validate"
+
+	apiErrors "toll/api/handler/errors"
+
+	"toll/internal/log"
+	"toll/internal/request"
+	"toll/internal/test"
+)
+
+// requestContext returns context of a request with the request ID.
+func requestContext(t *testing.T, rid string) context.Context {
+	t.Helper()
+
+	var ctx context.Context
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/toll-events", nil)
+	req.Header.Set("X-Request-ID", rid)
+
+	request.RequestId(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	})).ServeHTTP(httptest.NewRecorder(), req)
+
+	return ctx
+}
+
+func TestNewError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"api error", apiErrors.ErrAPINotFound.WithDetails("no fees found")},
+		{"security", &ogenerrors.SecurityError{Err: ogenerrors.ErrSecurityRequirementIsNotSatisfied}},
+		{"security api error", &ogenerrors.SecurityError{Err: apiErrors.ErrAPIUnauthorized}},
+		{"decode params", &ogenerrors.DecodeParamsError{
+			OperationContext: ogenerrors.OperationContext{Name: "GetTollEvents"},
+			Err:              &ogenerrors.DecodeParamError{Name: "limit", Err: errors.New("value 0 less than minimum 1")},
+		}},
+		{"decode request", &ogenerrors.DecodeRequestError{
+			OperationContext: ogenerrors.OperationContext{Name: "RecordTollEvents"},
+			Err:              &validate.Error{Fields: []validate.FieldError{{Name: "license_plate", Error: errors.New("required")}}},
+		}},
+		{"rate limit", apiErrors.ErrAPITooManyRequests},
+		{"unexpected", errors.New("connection refused")},
+	}
+
+	svc := &apiService{log: log.Noop()}
+	ctx := requestContext(t, "3f1c2a9e-8d4b-4c1e-9a57-6b2d0e7f1c44")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res := svc.NewError(ctx, tt.err)
+			body, err := res.Response.MarshalJSON()
+
+			test.Match(t, res.StatusCode, string(body), err)
+		})
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	t.Parallel()
+
+	svc := &apiService{log: log.Noop()}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/unknown", nil)
+	rec := httptest.NewRecorder()
+
+	svc.NotFound(rec, req)
+
+	test.Match(t, rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+}
@@ -2,29 +2,62 @@
 
 import (
 	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
 	"time"
 
+	"github.com/google/uuid"
+
 	apiErrors "toll/api/handler/errors"
 	"toll/api/mapper"
+	"toll/api/service"
+	"toll/api/types"
+	"toll/api/validation"
 
 	"toll/api/identity"
 	"toll/api/restapi"
 )
 
-func (s *apiService) RecordTollEvent(ctx context.Context, params *restapi.TollEvent) (restapi.RecordTollEventRes, error) {
-	kid := identity.Get(ctx)
-	if kid == nil {
+// idempotencyNamespace is the name space of event ids derived from idempotency keys.
+var idempotencyNamespace = uuid.MustParse("5b0f3c8e-4a57-4a0e-9c1d-7f6f2e9b8d21")
+
+func (s *apiService) RecordTollEvent(
+	ctx context.Context,
+	params *restapi.TollEvent,
+	opts restapi.RecordTollEventParams,
+) (restapi.RecordTollEventRes, error) {
+	principal := identity.Get(ctx)
+	if principal == nil {
 		return nil, apiErrors.ErrAPIUnauthorized
 	}
 
-	if !params.EventStart.Before(time.Now()) {
-		return nil, apiErrors.ErrAPIBadRequest.
-			WithDetails("toll event start date should be in the past")
+	tollEvent := mapper.ModelToTollEvent(params)
+
+	if errs := s.validator.TollEvent(tollEvent, time.Now()); len(errs) > 0 {
+		return nil, apiErrors.ErrAPIValidation.
+			WithDetails("toll event is invalid").
+			WithInvalidParams(invalidParams(errs)...)
+	}
+
+	if key, ok := opts.IdempotencyKey.Get(); ok && !tollEvent.EventId.Valid {
+		tollEvent.EventId = idempotentEventID(principal.KeyId, key)
+		tollEvent.BodyHash = bodyHash(params)
 	}
 
-	tollEvent := mapper.ModelToTollEvent(params)
+	if !tollEvent.IsTollFree() {
+		if resp, ok := s.billingBacklog(ctx); ok {
+			return resp, nil
+		}
+	}
 
 	err := s.tollEvents.Record(ctx, tollEvent)
+	if errors.Is(err, types.ErrEventIdReused) {
+		return nil, apiErrors.ErrAPIIdempotencyKeyReused.WithDetails("idempotency key reused with a different body")
+	}
+
 	if err != nil {
 		s.log.Errore(err)
 
@@ -32,8 +65,136 @@ func (s *apiService) RecordTollEvent(ctx context.Context, params *restapi.TollEv
 	}
 
 	if !tollEvent.IsTollFree() {
-		s.billing.TriggerFor(tollEvent.LicensePlate)
+		err = s.billing.TriggerFor(ctx, tollEvent.LicensePlate, tollEvent.EventStart)
+		if err != nil {
+			s.log.Errore(err)
+
+			return nil, apiErrors.ErrAPIInternal
+		}
 	}
 
 	return &restapi.RecordTollEventNoContent{}, nil
 }
+
+func (s *apiService) RecordTollEvents(
+	ctx context.Context,
+	req []restapi.TollEvent,
+	opts restapi.RecordTollEventsParams,
+) (restapi.RecordTollEventsRes, error) {
+	principal := identity.Get(ctx)
+	if principal == nil {
+		return nil, apiErrors.ErrAPIUnauthorized
+	}
+
+	now := time.Now()
+	results := make([]restapi.TollEventResult, len(req))
+	tollEvents := make([]*types.TollEvent, 0, len(req))
+
+	for i := range req {
+		results[i] = restapi.TollEventResult{
+			Index:  i,
+			Status: restapi.TollEventResultStatusAccepted,
+		}
+
+		tollEvent := mapper.ModelToTollEvent(&req[i])
+
+		if errs := s.validator.TollEvent(tollEvent, now); len(errs) > 0 {
+			results[i].Status = restapi.TollEventResultStatusRejected
+			results[i].Reason = restapi.NewOptString(errs.Error())
+
+			for _, fe := range errs {
+				results[i].InvalidParams = append(results[i].InvalidParams, restapi.InvalidParam{
+					Name:   fe.Field,
+					Reason: fe.Reason,
+				})
+			}
+
+			continue
+		}
+
+		if key, ok := opts.IdempotencyKey.Get(); ok && !tollEvent.EventId.Valid {
+			tollEvent.EventId = idempotentEventID(principal.KeyId, fmt.Sprintf("%s:%d", key, i))
+			tollEvent.BodyHash = bodyHash(&req[i])
+		}
+
+		tollEvents = append(tollEvents, tollEvent)
+	}
+
+	// Toll-free events are not billed, so they are recorded under billing backlog.
+	if slices.ContainsFunc(tollEvents, func(e *types.TollEvent) bool { return !e.IsTollFree() }) {
+		if resp, ok := s.billingBacklog(ctx); ok {
+			return resp, nil
+		}
+	}
+
+	err := s.tollEvents.RecordBatch(ctx, tollEvents)
+	if errors.Is(err, types.ErrEventIdReused) {
+		return nil, apiErrors.ErrAPIIdempotencyKeyReused.WithDetails("idempotency key reused with a different body")
+	}
+
+	if err != nil {
+		s.log.Errore(err)
+
+		return nil, apiErrors.ErrAPIInternal
+	}
+
+	err = s.billing.TriggerForEvents(ctx, tollEvents)
+	if err != nil {
+		s.log.Errore(err)
+
+		return nil, apiErrors.ErrAPIInternal
+	}
+
+	return &restapi.TollEventBatchResult{Results: results}, nil
+}
+
+// invalidParams returns API invalid params of the validation errors.
+func invalidParams(errs validation.Errors) []apiErrors.InvalidParam {
+	params := make([]apiErrors.InvalidParam, 0, len(errs))
+
+	for _, fe := range errs {
+		params = append(params, apiErrors.InvalidParam{Name: fe.Field, Reason: fe.Reason})
+	}
+
+	return params
+}
+
+// idempotentEventID derives event id from the client idempotency key. Keys are
+// scoped to the API key, so different clients may reuse the same key.
+func idempotentEventID(kid uuid.UUID, key string) uuid.NullUUID {
+	return uuid.NullUUID{
+		UUID:  uuid.NewSHA1(idempotencyNamespace, []byte(kid.String()+":"+key)),
+		Valid: true,
+	}
+}
+
+// bodyHash returns SHA-256 of the decoded toll event encoded again, so retries
+// formatting the same body differently are not taken as a different body.
+func bodyHash(event *restapi.TollEvent) []byte {
+	body, _ := event.MarshalJSON()
+	sum := sha256.Sum256(body)
+
+	return sum[:]
+}
+
+// billingBacklog returns 503 response when billing queue is backlogged. It is
+// checked before events are stored, so clients may retry rejected events.
+func (s *apiService) billingBacklog(ctx context.Context) (*restapi.R503Headers, bool) {
+	var backlog *service.BacklogError
+	if err := s.billing.CheckBacklog(); errors.As(err, &backlog) {
+		s.log.Warn(err)
+
+		return billingBacklogResponse(ctx, backlog), true
+	}
+
+	return nil, false
+}
+
+// billingBacklogResponse returns 503 response asking client to retry when billing catches up.
+func billingBacklogResponse(ctx context.Context, backlog *service.BacklogError) *restapi.R503Headers {
+	return &restapi.R503Headers{
+		RetryAfter: int32(math.Ceil(backlog.RetryAfter.Seconds())),
+		Response: problem(ctx, apiErrors.ErrAPIUnavailable.
+			WithDetails("billing queue is backlogged, retry later")),
+	}
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+
+	apiErrors "toll/api/handler/errors"
+	"toll/api/mapper"
+	"toll/api/types"
+	"toll/api/validation"
+
+	"toll/api/identity"
+	"toll/api/restapi"
+)
+
+func (s *apiService) GetTollEvents(ctx context.Context, params restapi.GetTollEventsParams) (restapi.GetTollEventsRes, error) {
+	principal := identity.Get(ctx)
+	if principal == nil {
+		return nil, apiErrors.ErrAPIUnauthorized
+	}
+
+	filter := types.TollEventFilter{
+		Limit: params.Limit.Or(100),
+	}
+
+	if plate, ok := params.LicensePlate.Get(); ok {
+		plate = validation.NormalizePlate(plate)
+		filter.LicensePlate = &plate
+	}
+
+	if vehicleType, ok := params.VehicleType.Get(); ok {
+		vt := types.VehicleType(vehicleType)
+		filter.VehicleType = &vt
+	}
+
+	if from, ok := params.From.Get(); ok {
+		filter.From = &from
+	}
+
+	if to, ok := params.To.Get(); ok {
+		filter.To = &to
+	}
+
+	if billed, ok := params.Billed.Get(); ok {
+		filter.Billed = &billed
+	}
+
+	if tollFree, ok := params.TollFree.Get(); ok {
+		filter.TollFree = &tollFree
+	}
+
+	if cursor, ok := params.Cursor.Get(); ok {
+		after, err := types.ParseTollEventCursor(cursor)
+		if err != nil {
+			return nil, apiErrors.ErrAPIValidation.
+				WithDetails("invalid cursor").
+				WithInvalidParams(apiErrors.InvalidParam{Name: "cursor", Reason: err.Error()})
+		}
+
+		filter.After = after
+	}
+
+	page, err := s.tollEvents.GetEvents(ctx, filter)
+	if err != nil {
+		s.log.Errore(err)
+
+		return nil, apiErrors.ErrAPIInternal
+	}
+
+	ret := &restapi.TollEventPage{
+		Items: mapper.TollEventsToModel(page.Events),
+	}
+
+	if page.Next != nil {
+		ret.NextCursor = restapi.NewOptString(page.Next.String())
+	}
+
+	return ret, nil
+}
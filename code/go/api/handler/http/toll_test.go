@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
+
+	"toll/api/identity"
+	"toll/api/restapi"
+	"toll/api/service"
+	mocks "toll/api/service/mocks"
+	"toll/api/types"
+	"toll/api/validation"
+
+	"toll/internal/log"
+	"toll/internal/test"
+)
+
+func TestRecordTollEvents_Backlog(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now().Add(-time.Hour).UTC()
+
+	tests := []struct {
+		name     string
+		vehicles []restapi.VehicleType
+		tollFree bool
+	}{
+		{"toll-free batch is recorded", []restapi.VehicleType{restapi.VehicleTypeMotorbike, restapi.VehicleTypeEmergency}, true},
+		{"batch with tolled event is rejected", []restapi.VehicleType{restapi.VehicleTypeMotorbike, restapi.VehicleTypeCar}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Define mocked executions.
+			billing := mocks.NewMockBillingService(t)
+			tollEvents := mocks.NewMockTollEventService(t)
+
+			if tt.tollFree {
+				tollEvents.EXPECT().RecordBatch(mock.Anything, mock.Anything).Return(nil)
+				billing.EXPECT().TriggerForEvents(mock.Anything, mock.Anything).Return(nil)
+			} else {
+				billing.EXPECT().CheckBacklog().Return(&service.BacklogError{Depth: 200, RetryAfter: 3 * time.Second})
+			}
+
+			svc := &apiService{
+				log:        log.Noop(),
+				tollEvents: tollEvents,
+				billing:    billing,
+				validator:  validation.New(validation.Rules{}),
+			}
+
+			req := make([]restapi.TollEvent, 0, len(tt.vehicles))
+			for _, vehicle := range tt.vehicles {
+				req = append(req, restapi.TollEvent{
+					LicensePlate: "ABC123",
+					EventStart:   start,
+					VehicleType:  vehicle,
+				})
+			}
+
+			ctx := identity.Set(requestContext(t, "rid-backlog"), identity.FromApiKey(&types.ApiKey{
+				Id:     uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+				Scopes: types.Scopes{types.ScopeEventsWrite},
+			}))
+
+			res, err := svc.RecordTollEvents(ctx, req, restapi.RecordTollEventsParams{})
+
+			test.Match(t, res, err)
+		})
+	}
+}
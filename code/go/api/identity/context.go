@@ -3,7 +3,7 @@
 import (
 	"context"
 
-	"github.com/google/uuid"
+	"toll/internal/request"
 )
 
 type authKey string
@@ -13,9 +13,9 @@
 	ServerPrincipalKey authKey = "ServerPrincipal"
 )
 
-// Get func returns signed api key id from the context.
-func Get(ctx context.Context) *uuid.UUID {
-	ret, ok := ctx.Value(ServerPrincipalKey).(*uuid.UUID)
+// Get func returns authenticated principal from the context.
+func Get(ctx context.Context) *Principal {
+	ret, ok := ctx.Value(ServerPrincipalKey).(*Principal)
 	if !ok {
 		return nil
 	}
@@ -23,7 +23,9 @@ func Get(ctx context.Context) *uuid.UUID {
 	return ret
 }
 
-// Set func writes kid to context.
-func Set(ctx context.Context, kid *uuid.UUID) context.Context {
-	return context.WithValue(ctx, ServerPrincipalKey, kid)
+// Set func writes principal to context and to the request log entry.
+func Set(ctx context.Context, p *Principal) context.Context {
+	request.SetLogFields(ctx, p.LogFields())
+
+	return context.WithValue(ctx, ServerPrincipalKey, p)
 }
@@ -0,0 +1,81 @@
+package identity
+
+import (
+	"github.com/google/uuid"
+
+	"toll/api/types"
+)
+
+// Kind tells what is calling the API.
+type Kind string
+
+// Kind values.
+const (
+	KindSystem   Kind = "system"
+	KindOperator Kind = "operator"
+	KindGantry   Kind = "gantry"
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// KeyId is id of the api key or device the caller authenticated with.
+	KeyId        uuid.UUID
+	Kind         Kind
+	Organisation string
+	Scopes       types.Scopes
+	GantryId     string
+}
+
+// FromApiKey returns principal of the api key. Keys assigned to a gantry act
+// as the gantry, other keys belong to operators unless they are system keys.
+func FromApiKey(key *types.ApiKey) *Principal {
+	ret := &Principal{
+		KeyId:        key.Id,
+		Kind:         KindOperator,
+		Organisation: key.Organisation,
+		Scopes:       key.Scopes,
+	}
+
+	switch {
+	case key.SystemKey:
+		ret.Kind = KindSystem
+	case key.GantryId != nil:
+		ret.Kind = KindGantry
+		ret.GantryId = *key.GantryId
+	}
+
+	return ret
+}
+
+// FromDevice returns principal of the gantry device.
+func FromDevice(device *types.Device) *Principal {
+	return &Principal{
+		KeyId:        device.Id,
+		Kind:         KindGantry,
+		Organisation: device.Organisation,
+		Scopes:       device.Scopes,
+		GantryId:     device.GantryId,
+	}
+}
+
+// LogFields returns fields identifying the principal in log entries.
+func (p *Principal) LogFields() map[string]string {
+	if p == nil {
+		return nil
+	}
+
+	ret := map[string]string{
+		"key_id":         p.KeyId.String(),
+		"principal_kind": string(p.Kind),
+	}
+
+	if p.Organisation != "" {
+		ret["organisation"] = p.Organisation
+	}
+
+	if p.GantryId != "" {
+		ret["gantry_id"] = p.GantryId
+	}
+
+	return ret
+}
@@ -0,0 +1,57 @@
+package identity
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"toll/api/types"
+	"toll/internal/test"
+)
+
+func TestFromApiKey(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d")
+	gantryId := "G-17"
+
+	tests := []struct {
+		name string
+		key  *types.ApiKey
+	}{
+		{"operator", &types.ApiKey{Id: id, Organisation: "Toll Operator AB", Scopes: types.Scopes{types.ScopeFeesRead}}},
+		{"gantry", &types.ApiKey{Id: id, Organisation: "Toll Operator AB", GantryId: &gantryId, Scopes: types.Scopes{types.ScopeEventsWrite}}},
+		{"system", &types.ApiKey{Id: id, SystemKey: true, Scopes: types.Scopes{types.ScopeKeysAdmin}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := FromApiKey(tt.key)
+
+			test.Match(t, p, p.LogFields())
+		})
+	}
+}
+
+func TestFromDevice(t *testing.T) {
+	t.Parallel()
+
+	p := FromDevice(&types.Device{
+		Id:           uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+		GantryId:     "G-17",
+		Organisation: "Toll Operator AB",
+		Scopes:       types.Scopes{types.ScopeEventsWrite},
+	})
+
+	test.Match(t, p, p.LogFields())
+}
+
+func TestGet_NotSet(t *testing.T) {
+	t.Parallel()
+
+	p := Get(t.Context())
+
+	test.Match(t, p, p.LogFields())
+}
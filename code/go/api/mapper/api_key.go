@@ -0,0 +1,72 @@
+package mapper
+
+import (
+	api "toll/api/restapi"
+	"toll/api/types"
+)
+
+func ModelToApiKey(c *api.ApiKeyCreate) *types.ApiKey {
+	if c == nil {
+		return nil
+	}
+
+	ret := &types.ApiKey{
+		Description:  c.Description.Or(""),
+		Organisation: c.Organisation.Or(""),
+		SystemKey:    c.SystemKey.Or(false),
+		Scopes:       make(types.Scopes, 0, len(c.Scopes)),
+		ExpiresAt:    c.ExpiresAt,
+	}
+
+	if gantryId, ok := c.GantryID.Get(); ok {
+		ret.GantryId = &gantryId
+	}
+
+	for _, scope := range c.Scopes {
+		ret.Scopes = append(ret.Scopes, types.Scope(scope))
+	}
+
+	return ret
+}
+
+func ApiKeyToModel(k *types.ApiKey) api.ApiKey {
+	ret := api.ApiKey{
+		ID:        k.Id,
+		SystemKey: k.SystemKey,
+		Scopes:    make([]api.Scope, 0, len(k.Scopes)),
+		ExpiresAt: k.ExpiresAt,
+		CreatedAt: k.CreatedAt,
+	}
+
+	for _, scope := range k.Scopes {
+		ret.Scopes = append(ret.Scopes, api.Scope(scope))
+	}
+
+	if k.Description != "" {
+		ret.Description = api.NewOptString(k.Description)
+	}
+
+	if k.Organisation != "" {
+		ret.Organisation = api.NewOptString(k.Organisation)
+	}
+
+	if k.GantryId != nil {
+		ret.GantryID = api.NewOptString(*k.GantryId)
+	}
+
+	if k.RevokedAt != nil {
+		ret.RevokedAt = api.NewOptDateTime(*k.RevokedAt)
+	}
+
+	return ret
+}
+
+func ApiKeysToModel(keys []*types.ApiKey) []api.ApiKey {
+	ret := make([]api.ApiKey, 0, len(keys))
+
+	for _, k := range keys {
+		ret = append(ret, ApiKeyToModel(k))
+	}
+
+	return ret
+}
@@ -0,0 +1,20 @@
+package mapper
+
+import (
+	api "toll/api/restapi"
+	"toll/api/types"
+)
+
+func DailyFeesToModel(fees []*types.DailyFee) []api.DailyFee {
+	ret := make([]api.DailyFee, 0, len(fees))
+
+	for _, fee := range fees {
+		ret = append(ret, api.DailyFee{
+			Date:         fee.Date,
+			LicensePlate: fee.LicensePlate,
+			Fee:          fee.Fee,
+		})
+	}
+
+	return ret
+}
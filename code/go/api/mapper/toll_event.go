@@ -1,7 +1,8 @@
 package mapper
 
 import (
-	"time"
+	"github.com/google/uuid"
+
 	api "toll/api/restapi"
 	"toll/api/types"
 )
@@ -14,10 +15,45 @@ func ModelToTollEvent(c *api.TollEvent) *types.TollEvent {
 	ret := &types.TollEvent{
 		LicensePlate: c.LicensePlate,
 		EventStart:   c.EventStart,
-		EventStop:    c.EventStart.Add(time.Hour),
 		VehicleType:  types.VehicleType(c.VehicleType),
 		Billed:       false,
 	}
 
+	if stop, ok := c.EventStop.Get(); ok {
+		ret.EventStop = &stop
+	}
+
+	if id, ok := c.EventID.Get(); ok {
+		ret.EventId = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	return ret
+}
+
+func TollEventToModel(e *types.TollEvent) api.TollEvent {
+	ret := api.TollEvent{
+		LicensePlate: e.LicensePlate,
+		EventStart:   e.EventStart,
+		VehicleType:  api.VehicleType(e.VehicleType),
+	}
+
+	if e.EventId.Valid {
+		ret.EventID = api.NewOptUUID(e.EventId.UUID)
+	}
+
+	if e.EventStop != nil {
+		ret.EventStop = api.NewOptNilDateTime(*e.EventStop)
+	}
+
+	return ret
+}
+
+func TollEventsToModel(events []*types.TollEvent) []api.TollEvent {
+	ret := make([]api.TollEvent, 0, len(events))
+
+	for _, e := range events {
+		ret = append(ret, TollEventToModel(e))
+	}
+
 	return ret
 }
@@ -2,6 +2,9 @@
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 
 	"toll/api/types"
 
@@ -13,6 +16,10 @@
 	// ApiKeyRepository interface with method definitions.
 	ApiKeyRepository interface {
 		Get(ctx context.Context, keyHash string) (*types.ApiKey, error)
+		List(ctx context.Context) ([]*types.ApiKey, error)
+		Create(ctx context.Context, key *types.ApiKey) error
+		Revoke(ctx context.Context, id uuid.UUID) (bool, error)
+		SetExpiry(ctx context.Context, id uuid.UUID, expiresAt time.Time) (*types.ApiKey, error)
 	}
 
 	apiKey struct {
@@ -20,6 +27,19 @@
 	}
 )
 
+// apiKeyColumns are selected for every api key, ids are stored as raw bytes.
+const apiKeyColumns = `
+			id,
+			key_hash,
+			description,
+			organisation,
+			gantry_id,
+			system_key,
+			scopes,
+			expires_at,
+			created_at,
+			revoked_at`
+
 // ApiKey func returns ApiKeyRepository with provided database connection.
 func ApiKey(db database.DB) ApiKeyRepository {
 	return &apiKey{db: db}
@@ -28,10 +48,7 @@ func ApiKey(db database.DB) ApiKeyRepository {
 // Get func returns an api key for the provided key hash value.
 func (r *apiKey) Get(ctx context.Context, keyHash string) (*types.ApiKey, error) {
 	query := `
-		SELECT
-			id,
-			key_hash,
-			expires_at
+		SELECT` + apiKeyColumns + `
 		FROM api_key
 		WHERE key_hash=$1`
 
@@ -44,3 +61,100 @@ func (r *apiKey) Get(ctx context.Context, keyHash string) (*types.ApiKey, error)
 
 	return &ret, nil
 }
+
+// List returns all api keys ordered by creation time.
+func (r *apiKey) List(ctx context.Context) ([]*types.ApiKey, error) {
+	query := `
+		SELECT` + apiKeyColumns + `
+		FROM api_key
+		ORDER BY created_at, id`
+
+	var keys []*types.ApiKey
+
+	err := r.db.Select(ctx, &keys, query)
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	return keys, nil
+}
+
+// Create stores a new api key.
+func (r *apiKey) Create(ctx context.Context, key *types.ApiKey) error {
+	insert := `
+		INSERT INTO api_key (
+			id,
+			key_hash,
+			description,
+			organisation,
+			gantry_id,
+			system_key,
+			scopes,
+			expires_at,
+			created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.db.Exec(ctx, insert,
+		key.Id[:],
+		key.KeyHash,
+		key.Description,
+		key.Organisation,
+		key.GantryId,
+		key.SystemKey,
+		key.Scopes,
+		key.ExpiresAt,
+		key.CreatedAt,
+	)
+	if err != nil {
+		return errlog.Error(err)
+	}
+
+	return nil
+}
+
+// Revoke marks the api key as revoked, keys revoked before keep their revocation
+// time. Returns false when there is no key with the id.
+func (r *apiKey) Revoke(ctx context.Context, id uuid.UUID) (bool, error) {
+	update := `
+		UPDATE api_key
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1
+	`
+
+	res, err := r.db.Exec(ctx, update, id[:])
+	if err != nil {
+		return false, errlog.Error(err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, errlog.Error(err)
+	}
+
+	return count > 0, nil
+}
+
+// SetExpiry changes expiry of the api key and returns the updated key or nil
+// when there is no key with the id.
+func (r *apiKey) SetExpiry(ctx context.Context, id uuid.UUID, expiresAt time.Time) (*types.ApiKey, error) {
+	update := `
+		UPDATE api_key
+		SET expires_at = $2
+		WHERE id = $1
+		RETURNING` + apiKeyColumns
+
+	var keys []*types.ApiKey
+
+	err := r.db.Select(ctx, &keys, update, id[:], expiresAt)
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	return keys[0], nil
+}
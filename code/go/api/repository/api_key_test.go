@@ -2,9 +2,12 @@
 
 import (
 	"context"
+	"database/sql/driver"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	mock "github.com/stretchr/testify/mock"
 
 	"toll/api/types"
@@ -62,3 +65,97 @@ func TestApiKey_Get_Error(t *testing.T) {
 	
 	test.Match(t, err)
 }
+
+func TestApiKey_Create(t *testing.T) {
+	t.Parallel()
+
+	gantryId := "G-1"
+
+	key := &types.ApiKey{
+		Id:           uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+		KeyHash:      "mock-api-key-hash",
+		Description:  "gantry 1",
+		Organisation: "Toll Operator AB",
+		GantryId:     &gantryId,
+		Scopes:       types.Scopes{types.ScopeEventsWrite},
+		ExpiresAt:    time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC),
+		CreatedAt:    time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC),
+	}
+
+	var args []interface{}
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.
+		EXPECT().
+		Exec(t.Context(), mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(_ context.Context, _ string, a ...interface{}) {
+			args = a
+		}).
+		Return(nil, nil)
+
+	// Create repository with mocked dependencies.
+	repo := ApiKey(db)
+
+	// Run Create() function and make assertions.
+	err := repo.Create(t.Context(), key)
+
+	test.Match(t, args, err)
+}
+
+func TestApiKey_Revoke(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d")
+
+	tests := []struct {
+		name     string
+		affected driver.RowsAffected
+	}{
+		{"revoked", 1},
+		{"not found", 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Define mocked executions.
+			db := database.NewMockDB(t)
+			db.
+				EXPECT().
+				Exec(t.Context(), mock.Anything, id[:]).
+				Return(tc.affected, nil)
+
+			// Create repository with mocked dependencies.
+			repo := ApiKey(db)
+
+			// Run Revoke() function and make assertions.
+			ok, err := repo.Revoke(t.Context(), id)
+
+			test.Match(t, ok, err)
+		})
+	}
+}
+
+func TestApiKey_SetExpiry_NotFound(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d")
+	expiresAt := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.
+		EXPECT().
+		Select(t.Context(), mock.Anything, mock.Anything, id[:], expiresAt).
+		Return(nil)
+
+	// Create repository with mocked dependencies.
+	repo := ApiKey(db)
+
+	// Run SetExpiry() function and make assertions.
+	ret, err := repo.SetExpiry(t.Context(), id, expiresAt)
+
+	test.Match(t, ret, err)
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+
+	"toll/api/types"
+
+	"toll/internal/database"
+	"toll/internal/errlog"
+)
+
+type (
+	// BillingJobRepository interface with method definitions.
+	BillingJobRepository interface {
+		Enqueue(ctx context.Context, license string, billingDate time.Time) error
+		Claim(ctx context.Context, limit int, lease time.Duration) ([]*types.BillingJob, error)
+		Complete(ctx context.Context, ids []int64) error
+		Retry(ctx context.Context, id int64, availableAt time.Time, reason string) error
+		DeadLetter(ctx context.Context, id int64, reason string) error
+		CountPending(ctx context.Context) (int, error)
+	}
+
+	billingJob struct {
+		db database.DB
+	}
+)
+
+// BillingJob func returns BillingJobRepository with provided database connection.
+func BillingJob(db database.DB) BillingJobRepository {
+	return &billingJob{db: db}
+}
+
+// Enqueue stores a pending billing job for the license plate and billing date.
+// Triggers are coalesced while the job is not claimed yet. Claimed jobs do not
+// absorb new triggers, so events recorded during billing are billed again.
+func (r *billingJob) Enqueue(ctx context.Context, license string, billingDate time.Time) error {
+	insert := `
+		INSERT INTO billing_jobs (license_plate, billing_date, status)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (license_plate, billing_date)
+			WHERE status = 'pending' AND attempts = 0
+			DO NOTHING
+	`
+
+	_, err := r.db.Exec(ctx, insert, license, billingDate, types.BillingJobPending)
+	if err != nil {
+		return errlog.Error(err)
+	}
+
+	return nil
+}
+
+// Claim leases up to limit available pending jobs. Jobs locked by other consumers
+// are skipped and leased jobs become available again when the lease expires.
+func (r *billingJob) Claim(ctx context.Context, limit int, lease time.Duration) ([]*types.BillingJob, error) {
+	query := `
+		UPDATE billing_jobs
+		SET available_at = NOW() + make_interval(secs => $3),
+		    attempts = attempts + 1
+		WHERE id IN (
+			SELECT id
+			FROM billing_jobs
+			WHERE status = $1
+			  AND available_at <= NOW()
+			ORDER BY available_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING
+			id,
+			license_plate,
+			billing_date,
+			status,
+			attempts,
+			last_error,
+			available_at,
+			created_at
+	`
+
+	var jobs []*types.BillingJob
+
+	err := r.db.Select(ctx, &jobs, query, types.BillingJobPending, limit, lease.Seconds())
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	return jobs, nil
+}
+
+// Complete removes processed jobs from the queue.
+func (r *billingJob) Complete(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := r.db.Exec(ctx, `DELETE FROM billing_jobs WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return errlog.Error(err)
+	}
+
+	return nil
+}
+
+// Retry makes failed job available again at the provided time.
+func (r *billingJob) Retry(ctx context.Context, id int64, availableAt time.Time, reason string) error {
+	update := `
+		UPDATE billing_jobs
+		SET available_at = $2,
+		    last_error = $3
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, update, id, availableAt, reason)
+	if err != nil {
+		return errlog.Error(err)
+	}
+
+	return nil
+}
+
+// DeadLetter moves failed job out of the queue for manual inspection.
+func (r *billingJob) DeadLetter(ctx context.Context, id int64, reason string) error {
+	update := `
+		UPDATE billing_jobs
+		SET status = $2,
+		    last_error = $3
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, update, id, types.BillingJobDead, reason)
+	if err != nil {
+		return errlog.Error(err)
+	}
+
+	return nil
+}
+
+// CountPending returns number of pending jobs, including the leased ones.
+func (r *billingJob) CountPending(ctx context.Context) (int, error) {
+	var count int
+
+	err := r.db.Get(ctx, &count, `SELECT COUNT(*) FROM billing_jobs WHERE status = $1`, types.BillingJobPending)
+	if err != nil {
+		return 0, errlog.Error(err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	mock "github.com/stretchr/testify/mock"
+
+	"toll/api/types"
+	database "toll/internal/database/mocks"
+	"toll/internal/test"
+)
+
+func TestBillingJob_Enqueue(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.EXPECT().
+		Exec(t.Context(), mock.Anything, "ABC123", day, types.BillingJobPending).
+		Return(nil, nil)
+
+	// Create repository with mocked dependencies.
+	repo := BillingJob(db)
+
+	// Run Enqueue() method.
+	err := repo.Enqueue(t.Context(), "ABC123", day)
+
+	test.Match(t, err)
+}
+
+func TestBillingJob_Enqueue_Error(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+
+	errTest := errors.New("test error")
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.EXPECT().
+		Exec(t.Context(), mock.Anything, "ABC123", day, types.BillingJobPending).
+		Return(nil, errTest)
+
+	// Create repository with mocked dependencies.
+	repo := BillingJob(db)
+
+	// Run Enqueue() method.
+	err := repo.Enqueue(t.Context(), "ABC123", day)
+
+	test.Match(t, err)
+}
+
+func TestBillingJob_Claim(t *testing.T) {
+	t.Parallel()
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.EXPECT().
+		Select(t.Context(), mock.Anything, mock.Anything, types.BillingJobPending, 100, float64(300)).
+		Return(nil)
+
+	// Create repository with mocked dependencies.
+	repo := BillingJob(db)
+
+	// Run Claim() method.
+	res, err := repo.Claim(t.Context(), 100, 5*time.Minute)
+
+	test.Match(t, res, err)
+}
+
+func TestBillingJob_Claim_Error(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.EXPECT().
+		Select(t.Context(), mock.Anything, mock.Anything, types.BillingJobPending, 100, float64(300)).
+		Return(errTest)
+
+	// Create repository with mocked dependencies.
+	repo := BillingJob(db)
+
+	// Run Claim() method.
+	_, err := repo.Claim(t.Context(), 100, 5*time.Minute)
+
+	test.Match(t, err)
+}
+
+func TestBillingJob_Complete(t *testing.T) {
+	t.Parallel()
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.EXPECT().
+		Exec(t.Context(), mock.Anything, pq.Array([]int64{1, 2})).
+		Return(nil, nil)
+
+	// Create repository with mocked dependencies.
+	repo := BillingJob(db)
+
+	// Run Complete() method, empty list does not reach the database.
+	errEmpty := repo.Complete(t.Context(), nil)
+	err := repo.Complete(t.Context(), []int64{1, 2})
+
+	test.Match(t, errEmpty, err)
+}
+
+func TestBillingJob_Retry_Error(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2025, 2, 3, 12, 0, 0, 0, time.UTC)
+
+	errTest := errors.New("test error")
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.EXPECT().
+		Exec(t.Context(), mock.Anything, int64(1), at, "billing failed").
+		Return(nil, errTest)
+
+	// Create repository with mocked dependencies.
+	repo := BillingJob(db)
+
+	// Run Retry() method.
+	err := repo.Retry(t.Context(), 1, at, "billing failed")
+
+	test.Match(t, err)
+}
+
+func TestBillingJob_DeadLetter_Error(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.EXPECT().
+		Exec(t.Context(), mock.Anything, int64(1), types.BillingJobDead, "billing failed").
+		Return(nil, errTest)
+
+	// Create repository with mocked dependencies.
+	repo := BillingJob(db)
+
+	// Run DeadLetter() method.
+	err := repo.DeadLetter(t.Context(), 1, "billing failed")
+
+	test.Match(t, err)
+}
+
+func TestBillingJob_CountPending_Error(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.EXPECT().
+		Get(t.Context(), mock.Anything, mock.Anything, types.BillingJobPending).
+		Return(errTest)
+
+	// Create repository with mocked dependencies.
+	repo := BillingJob(db)
+
+	// Run CountPending() method.
+	_, err := repo.CountPending(t.Context())
+
+	test.Match(t, err)
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"toll/api/types"
+
+	"toll/internal/database"
+	"toll/internal/errlog"
+)
+
+type (
+	// DailyFeeRepository interface with method definitions.
+	DailyFeeRepository interface {
+		GetForLicense(ctx context.Context, license string, from, to *time.Time, limit, offset int) ([]*types.DailyFee, error)
+		Exists(ctx context.Context, license string) (bool, error)
+	}
+
+	dailyFee struct {
+		db database.DB
+	}
+)
+
+// DailyFee func returns DailyFeeRepository with provided database connection.
+func DailyFee(db database.DB) DailyFeeRepository {
+	return &dailyFee{db: db}
+}
+
+// GetForLicense returns daily fees of the license plate for billing days in [from, to)
+// ordered by billing day. Nil bounds are open.
+func (r *dailyFee) GetForLicense(
+	ctx context.Context,
+	license string,
+	from, to *time.Time,
+	limit, offset int,
+) ([]*types.DailyFee, error) {
+	query := `
+		SELECT
+			date,
+			license_plate,
+			fee
+		FROM daily_toll_fees
+		WHERE license_plate = $1
+		  AND ($2::timestamptz IS NULL OR date >= $2)
+		  AND ($3::timestamptz IS NULL OR date < $3)
+		ORDER BY date
+		LIMIT $4
+		OFFSET $5
+	`
+
+	var fees []*types.DailyFee
+
+	err := r.db.Select(ctx, &fees, query, license, from, to, limit, offset)
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	return fees, nil
+}
+
+// Exists checks if the license plate has been billed at least once.
+func (r *dailyFee) Exists(ctx context.Context, license string) (bool, error) {
+	var exists bool
+
+	err := r.db.Get(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM daily_toll_fees WHERE license_plate = $1)`, license)
+	if err != nil {
+		return false, errlog.Error(err)
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	"toll/api/types"
+	database "toll/internal/database/mocks"
+	"toll/internal/test"
+)
+
+func TestDailyFee_GetForLicense(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.EXPECT().
+		Select(t.Context(), mock.Anything, mock.Anything, "ABC123", &from, &to, 10, 20).
+		Run(func(_ context.Context, dest interface{}, query string, args ...interface{}) {
+			f := dest.(*[]*types.DailyFee)
+			*f = []*types.DailyFee{
+				{Date: from, LicensePlate: "ABC123", Fee: 18},
+			}
+		}).
+		Return(nil)
+
+	// Create repository with mocked dependencies.
+	repo := DailyFee(db)
+
+	// Run GetForLicense() method.
+	res, err := repo.GetForLicense(t.Context(), "ABC123", &from, &to, 10, 20)
+
+	test.Match(t, res, err)
+}
+
+func TestDailyFee_GetForLicense_Error(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.EXPECT().
+		Select(t.Context(), mock.Anything, mock.Anything, "ABC123", (*time.Time)(nil), (*time.Time)(nil), 10, 0).
+		Return(errTest)
+
+	// Create repository with mocked dependencies.
+	repo := DailyFee(db)
+
+	// Run GetForLicense() method.
+	_, err := repo.GetForLicense(t.Context(), "ABC123", nil, nil, 10, 0)
+
+	test.Match(t, err)
+}
+
+func TestDailyFee_Exists(t *testing.T) {
+	t.Parallel()
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.EXPECT().
+		Get(t.Context(), mock.Anything, mock.Anything, "ABC123").
+		Run(func(_ context.Context, dest interface{}, query string, args ...interface{}) {
+			*dest.(*bool) = true
+		}).
+		Return(nil)
+
+	// Create repository with mocked dependencies.
+	repo := DailyFee(db)
+
+	// Run Exists() method.
+	res, err := repo.Exists(t.Context(), "ABC123")
+
+	test.Match(t, res, err)
+}
+
+func TestDailyFee_Exists_Error(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.EXPECT().
+		Get(t.Context(), mock.Anything, mock.Anything, "ABC123").
+		Return(errTest)
+
+	// Create repository with mocked dependencies.
+	repo := DailyFee(db)
+
+	// Run Exists() method.
+	_, err := repo.Exists(t.Context(), "ABC123")
+
+	test.Match(t, err)
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"toll/api/types"
+
+	"toll/internal/database"
+	"toll/internal/errlog"
+)
+
+type (
+	// DeviceRepository interface with method definitions.
+	DeviceRepository interface {
+		Get(ctx context.Context, id uuid.UUID) (*types.Device, error)
+		GetByCertificate(ctx context.Context, spki, subject string) (*types.Device, error)
+		ClaimNonce(ctx context.Context, id uuid.UUID, nonce string, expires, now time.Time) (bool, error)
+	}
+
+	device struct {
+		db database.DB
+	}
+)
+
+// deviceColumns are selected for every device, ids are stored as raw bytes.
+// Secrets are stored and read in plaintext, see the device table migration.
+const deviceColumns = `
+			id,
+			gantry_id,
+			secret,
+			description,
+			organisation,
+			scopes,
+			cert_spki,
+			cert_subject,
+			created_at,
+			revoked_at`
+
+// Device func returns DeviceRepository with provided database connection.
+func Device(db database.DB) DeviceRepository {
+	return &device{db: db}
+}
+
+// Get returns the device with the id or nil when there is none.
+func (r *device) Get(ctx context.Context, id uuid.UUID) (*types.Device, error) {
+	query := `
+		SELECT` + deviceColumns + `
+		FROM device
+		WHERE id = $1`
+
+	var devices []*types.Device
+
+	err := r.db.Select(ctx, &devices, query, id[:])
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	if len(devices) == 0 {
+		return nil, nil
+	}
+
+	return devices[0], nil
+}
+
+// GetByCertificate returns the device registered with the certificate public key
+// fingerprint or, when there is none, with the certificate subject.
+func (r *device) GetByCertificate(ctx context.Context, spki, subject string) (*types.Device, error) {
+	query := `
+		SELECT` + deviceColumns + `
+		FROM device
+		WHERE cert_spki = $1 OR cert_subject = $2
+		ORDER BY cert_spki = $1 DESC NULLS LAST
+		LIMIT 1`
+
+	var devices []*types.Device
+
+	err := r.db.Select(ctx, &devices, query, spki, subject)
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	if len(devices) == 0 {
+		return nil, nil
+	}
+
+	return devices[0], nil
+}
+
+// ClaimNonce records the signature nonce of the device until expires. It returns
+// false when the nonce is recorded and not expired yet. Expired nonces of the
+// device are dropped on the way, so they do not pile up.
+func (r *device) ClaimNonce(ctx context.Context, id uuid.UUID, nonce string, expires, now time.Time) (bool, error) {
+	query := `
+		WITH pruned AS (
+			DELETE FROM device_nonce
+			WHERE device_id = $1
+			  AND nonce <> $2
+			  AND expires_at <= $4
+		)
+		INSERT INTO device_nonce (device_id, nonce, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (device_id, nonce)
+		DO UPDATE SET expires_at = EXCLUDED.expires_at
+		WHERE device_nonce.expires_at <= $4
+		RETURNING true`
+
+	var claimed []bool
+
+	err := r.db.Select(ctx, &claimed, query, id[:], nonce, expires, now)
+	if err != nil {
+		return false, errlog.Error(err)
+	}
+
+	return len(claimed) > 0, nil
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
+
+	"toll/api/types"
+	database "toll/internal/database/mocks"
+	"toll/internal/test"
+)
+
+func TestDevice_Get(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d")
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.
+		EXPECT().
+		Select(t.Context(), mock.Anything, mock.Anything, id[:]).
+		Run(func(_ context.Context, dest interface{}, query string, args ...interface{}) {
+			devices := dest.(*[]*types.Device)
+			*devices = append(*devices, &types.Device{Id: id, GantryId: "G-17"})
+		}).
+		Return(nil)
+
+	// Create repository with mocked dependencies.
+	repo := Device(db)
+
+	// Run Get() function and make assertions.
+	ret, err := repo.Get(t.Context(), id)
+
+	test.Match(t, ret.Id, ret.GantryId, err)
+}
+
+func TestDevice_Get_NotFound(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d")
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.
+		EXPECT().
+		Select(t.Context(), mock.Anything, mock.Anything, id[:]).
+		Return(nil)
+
+	// Create repository with mocked dependencies.
+	repo := Device(db)
+
+	// Run Get() function and make assertions.
+	ret, err := repo.Get(t.Context(), id)
+
+	test.Match(t, ret, err)
+}
+
+func TestDevice_Get_Error(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d")
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.
+		EXPECT().
+		Select(t.Context(), mock.Anything, mock.Anything, id[:]).
+		Return(errors.New("mock select data from database error"))
+
+	// Create repository with mocked dependencies.
+	repo := Device(db)
+
+	// Run Get() function and make assertions.
+	_, err := repo.Get(t.Context(), id)
+
+	test.Match(t, err)
+}
+
+func TestDevice_GetByCertificate(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d")
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.
+		EXPECT().
+		Select(t.Context(), mock.Anything, mock.Anything, "mock-spki", "CN=G-17").
+		Run(func(_ context.Context, dest interface{}, query string, args ...interface{}) {
+			devices := dest.(*[]*types.Device)
+			*devices = append(*devices, &types.Device{Id: id, GantryId: "G-17"})
+		}).
+		Return(nil)
+
+	// Create repository with mocked dependencies.
+	repo := Device(db)
+
+	// Run GetByCertificate() function and make assertions.
+	ret, err := repo.GetByCertificate(t.Context(), "mock-spki", "CN=G-17")
+
+	test.Match(t, ret.Id, ret.GantryId, err)
+}
+
+func TestDevice_ClaimNonce(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d")
+	now := time.Date(2025, 2, 3, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(5 * time.Minute)
+
+	tests := []struct {
+		name    string
+		claimed []bool
+	}{
+		{"claimed", []bool{true}},
+		{"reused", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Define mocked executions.
+			db := database.NewMockDB(t)
+			db.
+				EXPECT().
+				Select(t.Context(), mock.Anything, mock.Anything, id[:], "n1", expires, now).
+				Run(func(_ context.Context, dest interface{}, query string, args ...interface{}) {
+					*dest.(*[]bool) = tt.claimed
+				}).
+				Return(nil)
+
+			// Create repository with mocked dependencies.
+			repo := Device(db)
+
+			// Run ClaimNonce() function and make assertions.
+			ret, err := repo.ClaimNonce(t.Context(), id, "n1", expires, now)
+
+			test.Match(t, ret, err)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"toll/api/types"
+
+	"toll/internal/database"
+	"toll/internal/errlog"
+)
+
+type (
+	// ExemptionDayRepository interface with method definitions.
+	ExemptionDayRepository interface {
+		GetAll(ctx context.Context) ([]*types.ExemptionDay, error)
+	}
+
+	exemptionDay struct {
+		db database.DB
+	}
+)
+
+// ExemptionDay func returns ExemptionDayRepository with provided database connection.
+func ExemptionDay(db database.DB) ExemptionDayRepository {
+	return &exemptionDay{db: db}
+}
+
+// GetAll returns all additional exemption days ordered by date.
+func (r *exemptionDay) GetAll(ctx context.Context) ([]*types.ExemptionDay, error) {
+	query := `
+		SELECT
+			date,
+			description
+		FROM exemption_days
+		ORDER BY date
+	`
+
+	var days []*types.ExemptionDay
+
+	err := r.db.Select(ctx, &days, query)
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	return days, nil
+}
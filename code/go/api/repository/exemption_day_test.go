@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	mock "github.com/stretchr/testify/mock"
+
+	database "toll/internal/database/mocks"
+	"toll/internal/test"
+)
+
+func TestExemptionDay_GetAll(t *testing.T) {
+	t.Parallel()
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.EXPECT().
+		Select(t.Context(), mock.Anything, mock.Anything).
+		Return(nil)
+
+	// Create repository with mocked dependencies.
+	repo := ExemptionDay(db)
+
+	// Run GetAll() method.
+	res, err := repo.GetAll(t.Context())
+
+	test.Match(t, res, err)
+}
+
+func TestExemptionDay_GetAll_Error(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.EXPECT().
+		Select(t.Context(), mock.Anything, mock.Anything).
+		Return(errTest)
+
+	// Create repository with mocked dependencies.
+	repo := ExemptionDay(db)
+
+	// Run GetAll() method.
+	_, err := repo.GetAll(t.Context())
+
+	test.Match(t, err)
+}
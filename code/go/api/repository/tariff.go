@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+
+	"toll/api/types"
+
+	"toll/internal/database"
+	"toll/internal/errlog"
+)
+
+type (
+	// TariffRepository interface with method definitions.
+	TariffRepository interface {
+		GetAll(ctx context.Context) (types.Tariffs, error)
+	}
+
+	tariff struct {
+		db database.DB
+	}
+)
+
+// Tariff func returns TariffRepository with provided database connection.
+func Tariff(db database.DB) TariffRepository {
+	return &tariff{db: db}
+}
+
+// GetAll returns all tariffs with their fees ordered by validity start.
+func (r *tariff) GetAll(ctx context.Context) (types.Tariffs, error) {
+	query := `
+		SELECT
+			id,
+			valid_from,
+			valid_to,
+			max_daily_fee
+		FROM tariffs
+		ORDER BY valid_from
+	`
+
+	var tariffs types.Tariffs
+
+	err := r.db.Select(ctx, &tariffs, query)
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	queryFees := `
+		SELECT
+			tariff_id,
+			start_hour,
+			start_minute,
+			end_hour,
+			end_minute,
+			fee
+		FROM tariff_fees
+		ORDER BY tariff_id, start_hour, start_minute
+	`
+
+	var fees []types.TariffFee
+
+	err = r.db.Select(ctx, &fees, queryFees)
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	// Attach fees to the tariffs they belong to.
+	byId := make(map[int64]*types.Tariff, len(tariffs))
+	for _, t := range tariffs {
+		byId[t.Id] = t
+	}
+
+	for _, fee := range fees {
+		if t, ok := byId[fee.TariffId]; ok {
+			t.Fees = append(t.Fees, fee)
+		}
+	}
+
+	return tariffs, nil
+}
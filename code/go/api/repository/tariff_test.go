@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	"toll/api/types"
+	database "toll/internal/database/mocks"
+	"toll/internal/test"
+)
+
+func TestTariff_GetAll(t *testing.T) {
+	t.Parallel()
+
+	validFrom := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.EXPECT().
+		Select(t.Context(), mock.AnythingOfType("*types.Tariffs"), mock.Anything).
+		Run(func(_ context.Context, dest interface{}, query string, args ...interface{}) {
+			ts := dest.(*types.Tariffs)
+			*ts = types.Tariffs{
+				{Id: 1, ValidFrom: validFrom, MaxDailyFee: 60},
+				{Id: 2, ValidFrom: validFrom.AddDate(0, 6, 0), MaxDailyFee: 70},
+			}
+		}).
+		Return(nil)
+	db.EXPECT().
+		Select(t.Context(), mock.AnythingOfType("*[]types.TariffFee"), mock.Anything).
+		Run(func(_ context.Context, dest interface{}, query string, args ...interface{}) {
+			fees := dest.(*[]types.TariffFee)
+			*fees = []types.TariffFee{
+				{TariffId: 1, StartHour: 6, StartMinute: 0, EndHour: 6, EndMinute: 29, Fee: 8},
+				{TariffId: 2, StartHour: 6, StartMinute: 0, EndHour: 6, EndMinute: 59, Fee: 10},
+				{TariffId: 3, StartHour: 7, StartMinute: 0, EndHour: 7, EndMinute: 59, Fee: 99},
+			}
+		}).
+		Return(nil)
+
+	// Create repository with mocked dependencies.
+	repo := Tariff(db)
+
+	// Run GetAll() method.
+	res, err := repo.GetAll(t.Context())
+
+	test.Match(t, res, err)
+}
+
+func TestTariff_GetAll_Error(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	// Define mocked executions.
+	db := database.NewMockDB(t)
+	db.EXPECT().
+		Select(t.Context(), mock.Anything, mock.Anything).
+		Return(errTest)
+
+	// Create repository with mocked dependencies.
+	repo := Tariff(db)
+
+	// Run GetAll() method.
+	_, err := repo.GetAll(t.Context())
+
+	test.Match(t, err)
+}
@@ -2,8 +2,12 @@
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
 	"toll/api/types"
 
 	"toll/internal/database"
@@ -15,10 +19,12 @@
 type (
 	// TollEventRepository interface with method definitions.
 	TollEventRepository interface {
-		GetAll(ctx context.Context, t time.Time, licenses []string) ([]*types.TollEvent, error)
-		GetAllForLicense(ctx context.Context, license string, t time.Time) ([]*types.TollEvent, error)
+		GetLicenses(ctx context.Context, from, to time.Time) ([]string, error)
+		GetEvents(ctx context.Context, filter types.TollEventFilter) ([]*types.TollEvent, error)
+		GetDailyFeeItems(ctx context.Context, license string, date time.Time) ([]*types.DailyFeeItem, error)
 		Record(ctx context.Context, event *types.TollEvent) error
-		UpdateDailyFee(ctx context.Context, dailyFee types.DailyFee) error
+		RecordBatch(ctx context.Context, events []*types.TollEvent) error
+		Bill(ctx context.Context, from, to time.Time, licenses []string, fees func(events []*types.TollEvent) []types.DailyFee) error
 	}
 
 	tollEvent struct {
@@ -31,8 +37,9 @@ func TollEvent(db database.DB) TollEventRepository {
 	return &tollEvent{db: db}
 }
 
-// GetAllForLicense returns all toll events for the given license plate at or after a specific time.
-func (r *tollEvent) GetAllForLicense(ctx context.Context, license string, t time.Time) ([]*types.TollEvent, error) {
+// GetEvents returns up to filter.Limit toll events matching the filter ordered by
+// event start, created at, license plate and event id, starting after filter.After.
+func (r *tollEvent) GetEvents(ctx context.Context, filter types.TollEventFilter) ([]*types.TollEvent, error) {
 	query := `
 		SELECT
 			created_at,
@@ -40,17 +47,54 @@ func (r *tollEvent) GetAllForLicense(ctx context.Context, license string, t time
 			event_start,
 			event_stop,
 			vehicle_type,
-			billed
+			billed,
+			event_id
 		FROM events
-		WHERE license_plate = $1
-		  AND event_start >= $2
-		  AND toll_free != true
-		ORDER BY event_start
+		WHERE ($1::text IS NULL OR license_plate = $1)
+		  AND ($2::text IS NULL OR vehicle_type = $2)
+		  AND ($3::timestamptz IS NULL OR event_start >= $3)
+		  AND ($4::timestamptz IS NULL OR event_start < $4)
+		  AND ($5::boolean IS NULL OR billed = $5)
+		  AND ($6::boolean IS NULL OR toll_free = $6)
+		  AND (
+			$7::timestamptz IS NULL
+			OR (event_start, created_at, license_plate, event_id) > ($7, $8::timestamptz, $9::text, $10::uuid)
+		  )
+		ORDER BY event_start, created_at, license_plate, event_id
+		LIMIT $11
 	`
 
+	var (
+		afterStart, afterCreated *time.Time
+		afterLicense             *string
+		afterId                  *uuid.UUID
+	)
+
+	if filter.After != nil {
+		afterStart = &filter.After.EventStart
+		afterCreated = &filter.After.CreatedAt
+		afterLicense = &filter.After.LicensePlate
+		afterId = &filter.After.EventId
+	}
+
 	var events []*types.TollEvent
 
-	err := r.db.Select(ctx, &events, query, license, t)
+	err := r.db.Select(
+		ctx,
+		&events,
+		query,
+		filter.LicensePlate,
+		filter.VehicleType,
+		filter.From,
+		filter.To,
+		filter.Billed,
+		filter.TollFree,
+		afterStart,
+		afterCreated,
+		afterLicense,
+		afterId,
+		filter.Limit,
+	)
 	if err != nil {
 		return nil, errlog.Error(err)
 	}
@@ -58,80 +102,290 @@ func (r *tollEvent) GetAllForLicense(ctx context.Context, license string, t time
 	return events, nil
 }
 
-// GetAll returns all toll events for the given license plate at or after a specific time.
-func (r *tollEvent) GetAll(ctx context.Context, t time.Time, licenses []string) ([]*types.TollEvent, error) {
+// GetLicenses returns license plates with not toll-free events starting in [from, to).
+func (r *tollEvent) GetLicenses(ctx context.Context, from, to time.Time) ([]string, error) {
 	query := `
-		SELECT
-			created_at,
-			license_plate,
-			event_start,
-			event_stop,
-			vehicle_type,
-			billed
+		SELECT DISTINCT license_plate
 		FROM events
 		WHERE event_start >= $1
-		  AND toll_free IS DISTINCT FROM true
-		  AND license_plate = ANY($2)
-		ORDER BY event_start
+		  AND event_start < $2
+		  AND toll_free = false
+		ORDER BY license_plate
 	`
 
-	var events []*types.TollEvent
+	var licenses []string
 
-	err := r.db.Select(ctx, &events, query, t, pq.Array(licenses))
+	err := r.db.Select(ctx, &licenses, query, from, to)
 	if err != nil {
 		return nil, errlog.Error(err)
 	}
 
-	return events, nil
+	return licenses, nil
 }
 
-// Record stores a car toll event.
+// GetDailyFeeItems returns fee breakdown of the license daily fee ordered by window start.
+func (r *tollEvent) GetDailyFeeItems(ctx context.Context, license string, date time.Time) ([]*types.DailyFeeItem, error) {
+	query := `
+		SELECT
+			date,
+			license_plate,
+			window_start,
+			events,
+			fee,
+			charged,
+			capped
+		FROM daily_toll_fee_items
+		WHERE license_plate = $1
+		  AND date = $2
+		ORDER BY window_start
+	`
+
+	var items []*types.DailyFeeItem
+
+	err := r.db.Select(ctx, &items, query, license, date)
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	return items, nil
+}
+
+// Record stores a car toll event. Replays of an event id are ignored.
 func (r *tollEvent) Record(ctx context.Context, event *types.TollEvent) error {
+	return r.RecordBatch(ctx, []*types.TollEvent{event})
+}
+
+// RecordBatch stores car toll events with a single multi-row insert. Events with
+// an event id that has been recorded before, or repeats in the batch, are ignored.
+// No event is stored and ErrEventIdReused is returned when an event id was recorded
+// before with a different body hash.
+func (r *tollEvent) RecordBatch(ctx context.Context, events []*types.TollEvent) error {
+	const columns = 9
+
+	now := time.Now()
+	seen := make(map[uuid.UUID]struct{}, len(events))
+	rows := make([]string, 0, len(events))
+	args := make([]interface{}, 0, len(events)*columns)
+
+	for _, event := range events {
+		if event.EventId.Valid {
+			if _, ok := seen[event.EventId.UUID]; ok {
+				continue
+			}
+
+			seen[event.EventId.UUID] = struct{}{}
+		}
+
+		n := len(args)
+		rows = append(rows, fmt.Sprintf(
+			"($%d::timestamptz, $%d::text, $%d::timestamptz, $%d::timestamptz, $%d::text, $%d::boolean, $%d::boolean, $%d::uuid, $%d::bytea)",
+			n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9,
+		))
+		args = append(args,
+			now,
+			event.LicensePlate,
+			event.EventStart,
+			event.EventStop,
+			event.VehicleType,
+			false,
+			event.IsTollFree(),
+			event.EventId,
+			event.BodyHash,
+		)
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	// Event ids are claimed in event_ids first, only events with a newly claimed
+	// or without an id are inserted. Events without an id get a generated one.
+	// Nothing is claimed or inserted when an id was claimed with another body.
 	insert := `
-		INSERT INTO events (
+		WITH batch (
 			created_at,
 			license_plate,
 			event_start,
 			event_stop,
 			vehicle_type,
 			billed,
-			toll_free
+			toll_free,
+			event_id,
+			body_hash
+		) AS (
+			VALUES ` + strings.Join(rows, ",\n\t\t\t\t") + `
+		), reused AS (
+			SELECT batch.event_id
+			FROM batch
+			JOIN event_ids ON event_ids.event_id = batch.event_id
+			WHERE event_ids.body_hash <> batch.body_hash
+		), claimed AS (
+			INSERT INTO event_ids (event_id, body_hash)
+			SELECT event_id, body_hash
+			FROM batch
+			WHERE event_id IS NOT NULL
+			  AND NOT EXISTS (SELECT 1 FROM reused)
+			ON CONFLICT (event_id) DO NOTHING
+			RETURNING event_id
+		), inserted AS (
+			INSERT INTO events (
+				created_at,
+				license_plate,
+				event_start,
+				event_stop,
+				vehicle_type,
+				billed,
+				toll_free,
+				event_id
+			)
+			SELECT
+				created_at,
+				license_plate,
+				event_start,
+				event_stop,
+				vehicle_type,
+				billed,
+				toll_free,
+				COALESCE(event_id, gen_random_uuid())
+			FROM batch
+			WHERE NOT EXISTS (SELECT 1 FROM reused)
+			  AND (event_id IS NULL OR event_id IN (SELECT event_id FROM claimed))
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		SELECT count(*) FROM reused
 	`
 
-	_, err := r.db.Exec(
-		ctx,
-		insert,
-		time.Now(),
-		event.LicensePlate,
-		event.EventStart,
-		event.EventStop,
-		event.VehicleType,
-		false,
-		event.IsTollFree(),
-	)
+	var reused int
+
+	err := r.db.Get(ctx, &reused, insert, args...)
 	if err != nil {
 		return errlog.Error(err)
 	}
 
+	if reused > 0 {
+		return errlog.Error(types.ErrEventIdReused)
+	}
+
 	return nil
 }
 
-func (r *tollEvent) UpdateDailyFee(ctx context.Context, dailyFee types.DailyFee) error {
-	insert := `
+// Bill flags not toll-free events of the licenses starting in [from, to) as billed
+// and stores daily fees computed by fees from all of them in one transaction.
+// Fee breakdown items of the daily fees are replaced as well.
+// Fees are recomputed from every event of the range, billed before or not, so
+// billing a range again replaces the daily fees instead of adding to them.
+func (r *tollEvent) Bill(
+	ctx context.Context,
+	from, to time.Time,
+	licenses []string,
+	fees func(events []*types.TollEvent) []types.DailyFee,
+) error {
+	// Locks are taken in license order, so concurrent billing of overlapping
+	// licenses cannot deadlock. They are released when the transaction ends.
+	lock := `
+		SELECT pg_advisory_xact_lock(hashtext(license_plate || $2))
+		FROM (
+			SELECT DISTINCT unnest($1::text[]) AS license_plate
+			ORDER BY 1
+		) AS plates
+	`
+
+	// Statements of the query share one snapshot, so flagged events are exactly
+	// the not yet billed ones among the selected events.
+	query := `
+		WITH flagged AS (
+			UPDATE events
+			SET billed = true
+			WHERE license_plate = ANY($3)
+			  AND event_start >= $1
+			  AND event_start < $2
+			  AND toll_free = false
+			  AND billed = false
+		)
+		SELECT
+			created_at,
+			license_plate,
+			event_start,
+			event_stop,
+			vehicle_type,
+			true AS billed
+		FROM events
+		WHERE license_plate = ANY($3)
+		  AND event_start >= $1
+		  AND event_start < $2
+		  AND toll_free = false
+		ORDER BY event_start
+	`
+
+	upsert := `
 		INSERT INTO daily_toll_fees (date, license_plate, fee)
-         	VALUES ($1, $2, $3)
+		VALUES ($1, $2, $3)
 		ON CONFLICT (date, license_plate)
 		DO UPDATE SET fee = EXCLUDED.fee
 	`
 
-	_, err := r.db.Exec(ctx,
-		insert,
-		dailyFee.Date,
-		dailyFee.LicensePlate,
-		dailyFee.Fee,
-	)
+	deleteItems := `
+		DELETE FROM daily_toll_fee_items
+		WHERE date = $1
+		  AND license_plate = $2
+	`
+
+	insertItems := `
+		INSERT INTO daily_toll_fee_items (
+			date,
+			license_plate,
+			window_start,
+			events,
+			fee,
+			charged,
+			capped
+		)
+		VALUES (
+			:date,
+			:license_plate,
+			:window_start,
+			:events,
+			:fee,
+			:charged,
+			:capped
+		)
+	`
+
+	err := r.db.Transaction(ctx, func(ctx context.Context, tx database.TX) error {
+		// Lock before reading events, so recomputation waits for billing of the
+		// same license and day in flight and sees the events it flagged.
+		if _, err := tx.Exec(lock, pq.Array(licenses), from.Format(time.DateOnly)); err != nil {
+			return err
+		}
+
+		var events []*types.TollEvent
 
-	return err
+		if err := tx.Select(&events, query, from, to, pq.Array(licenses)); err != nil {
+			return err
+		}
+
+		for _, fee := range fees(events) {
+			if _, err := tx.Exec(upsert, fee.Date, fee.LicensePlate, fee.Fee); err != nil {
+				return err
+			}
+
+			if _, err := tx.Exec(deleteItems, fee.Date, fee.LicensePlate); err != nil {
+				return err
+			}
+
+			if len(fee.Items) == 0 {
+				continue
+			}
+
+			if _, err := tx.NamedExec(insertItems, fee.Items); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errlog.Error(err)
+	}
+
+	return nil
 }
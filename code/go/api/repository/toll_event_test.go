@@ -1,101 +1,419 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 	mock "github.com/stretchr/testify/mock"
 
+	"toll/api/types"
+	sqldb "toll/internal/database"
 	database "toll/internal/database/mocks"
 	"toll/internal/test"
 )
 
-func TestGetAllForLicense_Success(t *testing.T) {
+func TestGetLicenses_Success(t *testing.T) {
 	t.Parallel()
 
-	license := "vk-123"
-
-	from := time.Now()
+	from := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
 
 	// Define mocked executions.
 	events := database.NewMockDB(t)
 	events.EXPECT().
-		Select(t.Context(), mock.Anything, mock.Anything, license, from).
+		Select(t.Context(), mock.Anything, mock.Anything, from, to).
+		Run(func(_ context.Context, dest interface{}, query string, args ...interface{}) {
+			l := dest.(*[]string)
+			*l = []string{"plate1", "plate2"}
+		}).
 		Return(nil)
 
 	// Create mocked event repository.
 	repo := TollEvent(events)
 
-	// Run GetAllForLicense() method.
-	res, err := repo.GetAllForLicense(t.Context(), license, from)
+	// Run GetLicenses() method.
+	res, err := repo.GetLicenses(t.Context(), from, to)
 
 	test.Match(t, res, err)
 }
 
-func TestGetAllForLicense_Error(t *testing.T) {
+func TestBill_Success(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	billed := []*types.TollEvent{
+		{LicensePlate: "plate1", EventStart: from.Add(7 * time.Hour), VehicleType: types.Car, Billed: true},
+		{LicensePlate: "plate1", EventStart: from.Add(9 * time.Hour), VehicleType: types.Car, Billed: true},
+	}
+
+	items := []types.DailyFeeItem{
+		{Date: from, LicensePlate: "plate1", WindowStart: from.Add(7 * time.Hour), Fee: 1, Charged: 1},
+		{Date: from, LicensePlate: "plate1", WindowStart: from.Add(9 * time.Hour), Fee: 1, Charged: 1},
+	}
+
+	// Define mocked executions.
+	tx := database.NewMockTX(t)
+	tx.EXPECT().Exec(mock.Anything, pq.Array([]string{"plate1", "plate2"}), "2025-02-03").Return(nil, nil)
+	tx.EXPECT().
+		Select(mock.Anything, mock.Anything, from, to, pq.Array([]string{"plate1", "plate2"})).
+		Run(func(dest interface{}, query string, args ...interface{}) {
+			e := dest.(*[]*types.TollEvent)
+			*e = billed
+		}).
+		Return(nil)
+	tx.EXPECT().Exec(mock.Anything, from, "plate1", 2).Return(nil, nil)
+	tx.EXPECT().Exec(mock.Anything, from, "plate1").Return(nil, nil)
+	tx.EXPECT().NamedExec(mock.Anything, items).Return(nil, nil)
+	tx.EXPECT().Exec(mock.Anything, from, "plate2", 0).Return(nil, nil)
+	tx.EXPECT().Exec(mock.Anything, from, "plate2").Return(nil, nil)
+
+	events := database.NewMockDB(t)
+	events.EXPECT().
+		Transaction(t.Context(), mock.Anything).
+		RunAndReturn(func(ctx context.Context, cb func(context.Context, sqldb.TX) error) error {
+			return cb(ctx, tx)
+		})
+
+	// Create mocked event repository.
+	repo := TollEvent(events)
+
+	// Run Bill() method with fee per event.
+	err := repo.Bill(t.Context(), from, to, []string{"plate1", "plate2"}, func(events []*types.TollEvent) []types.DailyFee {
+		count := make(map[string]int)
+		for _, ev := range events {
+			count[ev.LicensePlate]++
+		}
+
+		return []types.DailyFee{
+			{Date: from, LicensePlate: "plate1", Fee: count["plate1"], Items: items},
+			{Date: from, LicensePlate: "plate2", Fee: count["plate2"]},
+		}
+	})
+
+	test.Match(t, err)
+}
+
+func TestBill_Error(t *testing.T) {
 	t.Parallel()
 
-	license := "vk-123"
+	from := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	errTest := errors.New("test error")
+
+	// Define mocked executions.
+	tx := database.NewMockTX(t)
+	tx.EXPECT().Exec(mock.Anything, pq.Array([]string{"plate1"}), "2025-02-03").Return(nil, nil)
+	tx.EXPECT().
+		Select(mock.Anything, mock.Anything, from, to, pq.Array([]string{"plate1"})).
+		Return(nil)
+	tx.EXPECT().Exec(mock.Anything, from, "plate1", 0).Return(nil, errTest)
+
+	events := database.NewMockDB(t)
+	events.EXPECT().
+		Transaction(t.Context(), mock.Anything).
+		RunAndReturn(func(ctx context.Context, cb func(context.Context, sqldb.TX) error) error {
+			return cb(ctx, tx)
+		})
+
+	// Create mocked event repository.
+	repo := TollEvent(events)
+
+	// Run Bill() method.
+	err := repo.Bill(t.Context(), from, to, []string{"plate1"}, func([]*types.TollEvent) []types.DailyFee {
+		return []types.DailyFee{{Date: from, LicensePlate: "plate1"}}
+	})
 
-	from := time.Now()
+	test.Match(t, errors.Is(err, errTest))
+}
+
+func TestBill_LockError(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
 
 	errTest := errors.New("test error")
 
+	// Define mocked executions.
+	tx := database.NewMockTX(t)
+	tx.EXPECT().Exec(mock.Anything, pq.Array([]string{"plate1"}), "2025-02-03").Return(nil, errTest)
+
+	events := database.NewMockDB(t)
+	events.EXPECT().
+		Transaction(t.Context(), mock.Anything).
+		RunAndReturn(func(ctx context.Context, cb func(context.Context, sqldb.TX) error) error {
+			return cb(ctx, tx)
+		})
+
+	// Create mocked event repository.
+	repo := TollEvent(events)
+
+	// Run Bill() method, events are not read without the lock.
+	err := repo.Bill(t.Context(), from, to, []string{"plate1"}, func([]*types.TollEvent) []types.DailyFee {
+		t.Fatal("fees computed without lock")
+
+		return nil
+	})
+
+	test.Match(t, errors.Is(err, errTest))
+}
+
+func TestGetDailyFeeItems_Success(t *testing.T) {
+	t.Parallel()
+
+	date := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+
 	// Define mocked executions.
 	events := database.NewMockDB(t)
 	events.EXPECT().
-		Select(t.Context(), mock.Anything, mock.Anything, license, from).
+		Select(t.Context(), mock.Anything, mock.Anything, "plate1", date).
+		Run(func(_ context.Context, dest interface{}, query string, args ...interface{}) {
+			i := dest.(*[]*types.DailyFeeItem)
+			*i = []*types.DailyFeeItem{
+				{
+					Date:         date,
+					LicensePlate: "plate1",
+					WindowStart:  date.Add(7 * time.Hour),
+					Events: types.FeeItemEvents{
+						{EventStart: date.Add(7 * time.Hour), VehicleType: types.Car, Fee: 18},
+					},
+					Fee:     18,
+					Charged: 18,
+				},
+			}
+		}).
+		Return(nil)
+
+	// Create mocked event repository.
+	repo := TollEvent(events)
+
+	// Run GetDailyFeeItems() method.
+	res, err := repo.GetDailyFeeItems(t.Context(), "plate1", date)
+
+	test.Match(t, res, err)
+}
+
+func TestGetDailyFeeItems_Error(t *testing.T) {
+	t.Parallel()
+
+	date := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+
+	errTest := errors.New("test error")
+
+	// Define mocked executions.
+	events := database.NewMockDB(t)
+	events.EXPECT().
+		Select(t.Context(), mock.Anything, mock.Anything, "plate1", date).
 		Return(errTest)
 
 	// Create mocked event repository.
 	repo := TollEvent(events)
 
-	// Run GetAllForLicense() method.
-	_, err := repo.GetAllForLicense(t.Context(), license, from)
+	// Run GetDailyFeeItems() method.
+	_, err := repo.GetDailyFeeItems(t.Context(), "plate1", date)
 
 	test.Match(t, err)
 }
 
-func TestGetAll_Success(t *testing.T) {
+func TestGetEvents_Success(t *testing.T) {
 	t.Parallel()
 
-	from := time.Now()
+	plate := "plate1"
+	vehicleType := types.Car
+	billed := true
+	after := &types.TollEventCursor{
+		EventStart:   time.Date(2025, 2, 3, 7, 0, 0, 0, time.UTC),
+		CreatedAt:    time.Date(2025, 2, 3, 7, 0, 1, 0, time.UTC),
+		LicensePlate: plate,
+		EventId:      uuid.MustParse("3b241101-e2bb-4255-8caf-4136c566a962"),
+	}
+
+	filter := types.TollEventFilter{
+		LicensePlate: &plate,
+		VehicleType:  &vehicleType,
+		Billed:       &billed,
+		After:        after,
+		Limit:        10,
+	}
 
 	// Define mocked executions.
 	events := database.NewMockDB(t)
 	events.EXPECT().
-		Select(t.Context(), mock.Anything, mock.Anything, from, pq.Array([]string{"plate1", "plate2"})).
+		Select(
+			t.Context(), mock.Anything, mock.Anything,
+			&plate, &vehicleType, (*time.Time)(nil), (*time.Time)(nil), &billed, (*bool)(nil),
+			&after.EventStart, &after.CreatedAt, &after.LicensePlate, &after.EventId, 10,
+		).
+		Run(func(_ context.Context, dest interface{}, query string, args ...interface{}) {
+			e := dest.(*[]*types.TollEvent)
+			*e = []*types.TollEvent{
+				{LicensePlate: plate, EventStart: after.EventStart.Add(time.Hour), VehicleType: types.Car, Billed: true},
+			}
+		}).
 		Return(nil)
 
 	// Create mocked event repository.
 	repo := TollEvent(events)
 
-	// Run GetAll() method.
-	res, err := repo.GetAll(t.Context(), from, []string{"plate1", "plate2"})
+	// Run GetEvents() method.
+	res, err := repo.GetEvents(t.Context(), filter)
 
 	test.Match(t, res, err)
 }
 
-func TestGetAll_Error(t *testing.T) {
+func TestGetEvents_Error(t *testing.T) {
 	t.Parallel()
 
-	from := time.Now()
-
 	errTest := errors.New("test error")
 
 	// Define mocked executions.
 	events := database.NewMockDB(t)
 	events.EXPECT().
-		Select(t.Context(), mock.Anything, mock.Anything, from, pq.Array([]string{"plate1", "plate2"})).
+		Select(
+			t.Context(), mock.Anything, mock.Anything,
+			(*string)(nil), (*types.VehicleType)(nil), (*time.Time)(nil), (*time.Time)(nil), (*bool)(nil), (*bool)(nil),
+			(*time.Time)(nil), (*time.Time)(nil), (*string)(nil), (*uuid.UUID)(nil), 10,
+		).
 		Return(errTest)
 
 	// Create mocked event repository.
 	repo := TollEvent(events)
 
-	// Run GetAll() method.
-	_, err := repo.GetAll(t.Context(), from, []string{"plate1", "plate2"})
+	// Run GetEvents() method.
+	_, err := repo.GetEvents(t.Context(), types.TollEventFilter{Limit: 10})
+
+	test.Match(t, err)
+}
+
+func TestRecordBatch_Success(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 2, 3, 7, 0, 0, 0, time.UTC)
+	stop := start.Add(time.Minute)
+
+	batch := []*types.TollEvent{
+		{LicensePlate: "plate1", EventStart: start, EventStop: &stop, VehicleType: types.Car},
+		{LicensePlate: "plate2", EventStart: start, EventStop: &stop, VehicleType: types.Tractor},
+	}
+
+	var (
+		query string
+		args  []interface{}
+	)
+
+	// Nine columns are inserted for every event.
+	anyArgs := make([]interface{}, 9*len(batch))
+	for i := range anyArgs {
+		anyArgs[i] = mock.Anything
+	}
+
+	// Define mocked executions.
+	events := database.NewMockDB(t)
+	events.EXPECT().
+		Get(t.Context(), mock.Anything, mock.Anything, anyArgs...).
+		Run(func(_ context.Context, _ interface{}, q string, a ...interface{}) {
+			query, args = q, a
+		}).
+		Return(nil)
+
+	// Create mocked event repository.
+	repo := TollEvent(events)
+
+	// Run RecordBatch() method.
+	err := repo.RecordBatch(t.Context(), batch)
+
+	// Skip created_at of every row.
+	test.Match(t, query, args[1:9], args[10:], err)
+}
+
+func TestRecordBatch_DuplicateEventIds(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 2, 3, 7, 0, 0, 0, time.UTC)
+	stop := start.Add(time.Minute)
+	eventId := uuid.NullUUID{UUID: uuid.MustParse("0b6a8f2e-6f0c-4d52-9d0e-2b9f1d4c7a11"), Valid: true}
+
+	batch := []*types.TollEvent{
+		{EventId: eventId, LicensePlate: "plate1", EventStart: start, EventStop: &stop, VehicleType: types.Car},
+		{EventId: eventId, LicensePlate: "plate1", EventStart: start, EventStop: &stop, VehicleType: types.Car},
+		{LicensePlate: "plate2", EventStart: start, EventStop: &stop, VehicleType: types.Car},
+	}
+
+	var args []interface{}
+
+	// Repeated event id is inserted once.
+	anyArgs := make([]interface{}, 9*2)
+	for i := range anyArgs {
+		anyArgs[i] = mock.Anything
+	}
+
+	// Define mocked executions.
+	events := database.NewMockDB(t)
+	events.EXPECT().
+		Get(t.Context(), mock.Anything, mock.Anything, anyArgs...).
+		Run(func(_ context.Context, _ interface{}, _ string, a ...interface{}) {
+			args = a
+		}).
+		Return(nil)
+
+	// Create mocked event repository.
+	repo := TollEvent(events)
+
+	// Run RecordBatch() method.
+	err := repo.RecordBatch(t.Context(), batch)
+
+	// Skip created_at of every row.
+	test.Match(t, args[1:9], args[10:], err)
+}
+
+func TestRecordBatch_EventIdReused(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 2, 3, 7, 0, 0, 0, time.UTC)
+	eventId := uuid.NullUUID{UUID: uuid.MustParse("0b6a8f2e-6f0c-4d52-9d0e-2b9f1d4c7a11"), Valid: true}
+
+	batch := []*types.TollEvent{
+		{EventId: eventId, LicensePlate: "plate1", EventStart: start, VehicleType: types.Car, BodyHash: []byte{1}},
+	}
+
+	anyArgs := make([]interface{}, 9)
+	for i := range anyArgs {
+		anyArgs[i] = mock.Anything
+	}
+
+	// Define mocked executions.
+	events := database.NewMockDB(t)
+	events.EXPECT().
+		Get(t.Context(), mock.Anything, mock.Anything, anyArgs...).
+		Run(func(_ context.Context, dest interface{}, _ string, _ ...interface{}) {
+			*dest.(*int) = 1
+		}).
+		Return(nil)
+
+	// Create mocked event repository.
+	repo := TollEvent(events)
+
+	// Run RecordBatch() method.
+	err := repo.RecordBatch(t.Context(), batch)
+
+	test.Match(t, errors.Is(err, types.ErrEventIdReused))
+}
+
+func TestRecordBatch_Empty(t *testing.T) {
+	t.Parallel()
+
+	// Create mocked event repository without expected executions.
+	repo := TollEvent(database.NewMockDB(t))
+
+	// Run RecordBatch() method.
+	err := repo.RecordBatch(t.Context(), nil)
 
 	test.Match(t, err)
 }
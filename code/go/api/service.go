@@ -4,11 +4,10 @@
 	"context"
 	"math/rand"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
 	"toll/api/audit"
 	"toll/api/auth"
 	"toll/api/config"
@@ -17,6 +16,7 @@
 	"toll/api/service"
 
 	"toll/internal/database"
+	"toll/internal/errlog"
 	"toll/internal/log"
 	"toll/internal/request"
 	"toll/internal/sigctx"
@@ -49,56 +49,154 @@ func Start() error {
 
 	log.WithFields(log.Fields{"address": flags.Svc.Addr}).Info("starting API service")
 
-	go StartListener()
+	stopped := make(chan struct{})
+
+	go func() {
+		StartListener(deadline)
+		close(stopped)
+	}()
+
+	go StartKeyInvalidation(deadline)
 
 	<-deadline.Done()
 	log.Print("stopping API service")
 
+	// Stop accepting toll events before draining billing workers.
+	<-stopped
+
+	stopBilling()
+
 	return nil
 }
 
-func StartListener() {
+// Rebill enqueues billing jobs recomputing daily fees of the rebill day, if one is
+// set, and stops billing workers. Jobs are stored in the database and billed by
+// running API replicas, so the API exits after a rebill instead of starting.
+func Rebill() (bool, error) {
+	day, ok := flags.Billing.RebillDay()
+	if !ok {
+		return false, nil
+	}
+
+	defer stopBilling()
+
+	count, err := service.Billing.Rebill(context.Background(), day)
+	if err != nil {
+		return true, err
+	}
+
+	log.Infof("rebilling %d licenses on %s", count, day.Format(time.DateOnly))
+
+	return true, nil
+}
+
+// stopBilling waits for billing workers to finish in-flight jobs until the drain timeout.
+func stopBilling() {
+	ctx, cancel := context.WithTimeout(context.Background(), flags.Billing.DrainTimeout)
+	defer cancel()
+
+	if err := service.Billing.Stop(ctx); err != nil {
+		log.WithFields(errlog.StackLog(err)).Warne(err, "billing workers did not drain")
+	}
+}
+
+// StartKeyInvalidation drops cached api keys changed in the database until the
+// deadline is done. Without notifications cached keys only expire by their ttl.
+func StartKeyInvalidation(deadline sigctx.Ctx) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-deadline.Done()
+		cancel()
+	}()
+
+	err := database.Listen(ctx, service.ApiKeyChannel, service.KeyCache)
+	if err != nil {
+		log.WithFields(errlog.StackLog(err)).Warne(err, "api key cache invalidation disabled")
+	}
+}
+
+// StartListener serves HTTP requests until the deadline is done.
+func StartListener(deadline sigctx.Ctx) {
 	authorization := auth.Get()
 
+	handlers := httpHandler.NewApiHandlers()
+
 	handler, err := restapi.NewServer(
-		httpHandler.NewApiHandlers(),
+		handlers,
 		authorization,
 		restapi.WithPathPrefix("/api/v1"),
 		restapi.WithMiddleware(audit.Middleware),
+		restapi.WithErrorHandler(handlers.ErrorHandler),
+		restapi.WithNotFound(handlers.NotFound),
+		restapi.WithMethodNotAllowed(handlers.MethodNotAllowed),
 	)
 	if err != nil {
 		log.Fatale(err, "error creating handler")
 	}
 
-	h := request.Logger(handler)
+	h := auth.Signed(handler)
+	h = auth.ClientCertificate(h)
+	h = request.Logger(h)
 	h = request.RequestId(h)
 
-	mux := http.NewServeMux()
-	mux.Handle("/", h)
-
-	srv := &http.Server{
+	servers := []*http.Server{{
 		Addr:         flags.Svc.Addr,
-		Handler:      mux,
+		Handler:      h,
 		ReadTimeout:  10 * time.Minute,
 		WriteTimeout: 10 * time.Minute,
+	}}
+
+	// Metrics are served on an internal address only, never on the public or device listeners.
+	if flags.Svc.MetricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+
+		servers = append(servers, &http.Server{
+			Addr:         flags.Svc.MetricsAddr,
+			Handler:      mux,
+			ReadTimeout:  time.Minute,
+			WriteTimeout: time.Minute,
+		})
+	}
+
+	// Devices may connect directly with client certificates instead of through the proxy.
+	if flags.TLS.Enabled() {
+		clientCAs, err := flags.TLS.ClientCAs()
+		if err != nil {
+			log.Fatale(err, "error loading client CAs")
+		}
+
+		servers = append(servers, &http.Server{
+			Addr:         flags.TLS.Addr,
+			Handler:      h,
+			TLSConfig:    auth.ServerTLS(clientCAs),
+			ReadTimeout:  10 * time.Minute,
+			WriteTimeout: 10 * time.Minute,
+		})
 	}
 
-	log.Infof("Listening on %s%s", flags.Svc.Domain, flags.Svc.Addr)
+	for _, srv := range servers {
+		log.Infof("Listening on %s%s", flags.Svc.Domain, srv.Addr)
 
-	// Listen to OS signals for program termination.
-	osSignal := make(chan os.Signal, 1)
-	signal.Notify(osSignal, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			var err error
 
-	go func() {
-		//nolint
-		if err = srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatale(err)
-		}
-	}()
+			if srv.TLSConfig != nil {
+				err = srv.ListenAndServeTLS(flags.TLS.CertFile, flags.TLS.KeyFile)
+			} else {
+				err = srv.ListenAndServe()
+			}
+
+			if err != nil && err != http.ErrServerClosed {
+				log.Fatale(err)
+			}
+		}()
+	}
 
 	log.Info("Server started")
 
-	<-osSignal
+	<-deadline.Done()
 
 	log.Info("Server shutting down")
 
@@ -108,8 +206,10 @@ func StartListener() {
 
 	defer cancelCtxShutdown()
 
-	if err = srv.Shutdown(ctxShutdown); err != nil {
-		log.Fatale(err, "error gracefully shutting down the server")
+	for _, srv := range servers {
+		if err := srv.Shutdown(ctxShutdown); err != nil {
+			log.Fatale(err, "error gracefully shutting down the server")
+		}
 	}
 
 	log.Info("Server exited properly")
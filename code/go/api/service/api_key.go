@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/google/uuid"
+
+	"toll/api/repository"
+	"toll/api/types"
+
+	"toll/internal/database"
+	"toll/internal/errlog"
+)
+
+// apiKeyBytes is number of random bytes of generated api keys.
+const apiKeyBytes = 32
+
+type (
+	// ApiKeyService interface with method definitions.
+	ApiKeyService interface {
+		Create(ctx context.Context, key *types.ApiKey) (string, error)
+		List(ctx context.Context) ([]*types.ApiKey, error)
+		Revoke(ctx context.Context, id uuid.UUID) error
+		SetExpiry(ctx context.Context, id uuid.UUID, expiresAt time.Time) (*types.ApiKey, error)
+	}
+
+	apiKeys struct {
+		keys repository.ApiKeyRepository
+	}
+)
+
+// ApiKey func returns new ApiKeyService managing keys in the database.
+func ApiKey() ApiKeyService {
+	db := database.Get()
+
+	return &apiKeys{
+		keys: repository.ApiKey(db),
+	}
+}
+
+// Create generates id and plaintext of the key, stores its hash and returns the
+// plaintext. The plaintext is not stored and cannot be retrieved again.
+func (svc *apiKeys) Create(ctx context.Context, key *types.ApiKey) (string, error) {
+	secret := make([]byte, apiKeyBytes)
+
+	if _, err := rand.Read(secret); err != nil {
+		return "", errlog.Error(err)
+	}
+
+	plaintext := base64.RawURLEncoding.EncodeToString(secret)
+
+	key.Id = uuid.New()
+	key.KeyHash = hashApiKey(plaintext)
+	key.CreatedAt = time.Now()
+	key.RevokedAt = nil
+
+	if err := svc.keys.Create(ctx, key); err != nil {
+		return "", errlog.Error(err)
+	}
+
+	return plaintext, nil
+}
+
+// List returns all api keys, including expired and revoked ones.
+func (svc *apiKeys) List(ctx context.Context) ([]*types.ApiKey, error) {
+	keys, err := svc.keys.List(ctx)
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	return keys, nil
+}
+
+// Revoke revokes the api key. ErrNotFound is returned when there is no such key.
+func (svc *apiKeys) Revoke(ctx context.Context, id uuid.UUID) error {
+	ok, err := svc.keys.Revoke(ctx, id)
+	if err != nil {
+		return errlog.Error(err)
+	}
+
+	if !ok {
+		return errlog.Error(ErrNotFound)
+	}
+
+	return nil
+}
+
+// SetExpiry changes expiry of the api key. ErrNotFound is returned when there is no such key.
+func (svc *apiKeys) SetExpiry(ctx context.Context, id uuid.UUID, expiresAt time.Time) (*types.ApiKey, error) {
+	key, err := svc.keys.SetExpiry(ctx, id, expiresAt)
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	if key == nil {
+		return nil, errlog.Error(ErrNotFound)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"toll/api/types"
+)
+
+// ApiKeyChannel is the database channel notified with hashes of changed api keys.
+const ApiKeyChannel = "api_key_changed"
+
+var (
+	apiKeyCacheRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: prometheus.BuildFQName(namespace, "api_key_cache", "requests_total"),
+			Help: "Number of api key cache lookups by result.",
+		},
+		[]string{"result"},
+	)
+
+	apiKeyCacheInvalidations = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: prometheus.BuildFQName(namespace, "api_key_cache", "invalidations_total"),
+			Help: "Number of api key cache entries dropped by change notifications.",
+		},
+	)
+)
+
+type (
+	// ApiKeyCache holds api keys looked up by their hash. Unknown hashes are
+	// cached as well, for a separate and usually shorter time.
+	ApiKeyCache struct {
+		mu      sync.Mutex
+		entries map[string]apiKeyCacheEntry
+
+		// generation counts invalidations, so keys read from the database
+		// before an invalidation are not cached after it.
+		generation uint64
+
+		ttl         time.Duration
+		negativeTTL time.Duration
+		size        int
+
+		now func() time.Time
+	}
+
+	apiKeyCacheEntry struct {
+		key     *types.ApiKey
+		expires time.Time
+	}
+)
+
+// NewApiKeyCache returns cache keeping at most size keys for ttl and unknown
+// hashes for negativeTTL. Zero ttl disables caching.
+func NewApiKeyCache(ttl, negativeTTL time.Duration, size int) *ApiKeyCache {
+	return &ApiKeyCache{
+		entries:     make(map[string]apiKeyCacheEntry),
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		size:        size,
+		now:         time.Now,
+	}
+}
+
+// Get returns the cached key of the hash, nil key is a cached unknown hash.
+func (c *ApiKeyCache) Get(hash string) (*types.ApiKey, bool) {
+	if c == nil || c.ttl <= 0 {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[hash]
+	if ok && !c.now().Before(entry.expires) {
+		delete(c.entries, hash)
+
+		ok = false
+	}
+
+	if !ok {
+		apiKeyCacheRequests.WithLabelValues("miss").Inc()
+
+		return nil, false
+	}
+
+	apiKeyCacheRequests.WithLabelValues("hit").Inc()
+
+	return entry.key, true
+}
+
+// Generation returns the current invalidation generation, it is read before
+// looking the key up in the database and passed to Put.
+func (c *ApiKeyCache) Generation() uint64 {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation
+}
+
+// Put caches the key of the hash, nil key caches the hash as unknown. The key is
+// not cached when any key was invalidated since the generation, as it may be stale.
+func (c *ApiKeyCache) Put(hash string, key *types.ApiKey, generation uint64) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+
+	ttl := c.ttl
+	if key == nil {
+		ttl = c.negativeTTL
+	}
+
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	now := c.now()
+
+	if _, ok := c.entries[hash]; !ok && len(c.entries) >= c.size {
+		c.evict(now)
+	}
+
+	c.entries[hash] = apiKeyCacheEntry{
+		key:     key,
+		expires: now.Add(ttl),
+	}
+}
+
+// Notify drops the key of the changed hash, implements database.Listener.
+func (c *ApiKeyCache) Notify(hash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	if _, ok := c.entries[hash]; ok {
+		delete(c.entries, hash)
+		apiKeyCacheInvalidations.Inc()
+	}
+}
+
+// Reset drops all cached keys, implements database.Listener.
+func (c *ApiKeyCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	apiKeyCacheInvalidations.Add(float64(len(c.entries)))
+	clear(c.entries)
+}
+
+// evict drops expired entries, or an arbitrary one when none is expired.
+func (c *ApiKeyCache) evict(now time.Time) {
+	for hash, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, hash)
+		}
+	}
+
+	for hash := range c.entries {
+		if len(c.entries) < c.size {
+			break
+		}
+
+		delete(c.entries, hash)
+	}
+}
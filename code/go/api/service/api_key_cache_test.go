@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	repository "toll/api/repository/mocks"
+	"toll/api/types"
+	"toll/internal/test"
+)
+
+func TestValidateApiKey_Cached(t *testing.T) {
+	t.Parallel()
+
+	// Define mocked executions.
+	keys := repository.NewMockApiKeyRepository(t)
+	keys.EXPECT().
+		Get(t.Context(), hashApiKey("secret")).
+		Return(&types.ApiKey{
+			Id:        uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil).
+		Once()
+
+	svc := &auth{keys: keys, cache: NewApiKeyCache(time.Minute, time.Second, 10)}
+
+	first, errFirst := svc.ValidateApiKey(t.Context(), "secret")
+	second, errSecond := svc.ValidateApiKey(t.Context(), "secret")
+
+	test.Match(t, first.Id, errFirst, second == first, errSecond)
+}
+
+func TestValidateApiKey_NegativeCached(t *testing.T) {
+	t.Parallel()
+
+	// Define mocked executions.
+	keys := repository.NewMockApiKeyRepository(t)
+	keys.EXPECT().
+		Get(t.Context(), hashApiKey("unknown")).
+		Return(&types.ApiKey{}, nil).
+		Once()
+
+	svc := &auth{keys: keys, cache: NewApiKeyCache(time.Minute, time.Second, 10)}
+
+	_, errFirst := svc.ValidateApiKey(t.Context(), "unknown")
+	_, errSecond := svc.ValidateApiKey(t.Context(), "unknown")
+
+	test.Match(t, errors.Is(errFirst, ErrIncorrectApiKey), errors.Is(errSecond, ErrIncorrectApiKey))
+}
+
+func TestValidateApiKey_CachedExpired(t *testing.T) {
+	t.Parallel()
+
+	cache := NewApiKeyCache(time.Minute, time.Second, 10)
+	cache.Put(hashApiKey("secret"), &types.ApiKey{
+		Id:        uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+		ExpiresAt: time.Now().Add(-time.Second),
+	}, cache.Generation())
+
+	svc := &auth{keys: repository.NewMockApiKeyRepository(t), cache: cache}
+
+	key, err := svc.ValidateApiKey(t.Context(), "secret")
+
+	test.Match(t, key, errors.Is(err, ErrApiKeyExpired))
+}
+
+func TestApiKeyCache(t *testing.T) {
+	t.Parallel()
+
+	key := &types.ApiKey{Id: uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d")}
+	now := time.Date(2025, 2, 3, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		run  func(c *ApiKeyCache)
+		hash string
+	}{
+		{
+			name: "hit",
+			run:  func(c *ApiKeyCache) { c.Put("a", key, c.Generation()) },
+			hash: "a",
+		},
+		{
+			name: "negative hit",
+			run:  func(c *ApiKeyCache) { c.Put("a", nil, c.Generation()) },
+			hash: "a",
+		},
+		{
+			name: "miss",
+			run:  func(c *ApiKeyCache) { c.Put("a", key, c.Generation()) },
+			hash: "b",
+		},
+		{
+			name: "expired",
+			run: func(c *ApiKeyCache) {
+				c.Put("a", key, c.Generation())
+				c.now = func() time.Time { return now.Add(time.Minute) }
+			},
+			hash: "a",
+		},
+		{
+			name: "negative expired",
+			run: func(c *ApiKeyCache) {
+				c.Put("a", nil, c.Generation())
+				c.now = func() time.Time { return now.Add(time.Second) }
+			},
+			hash: "a",
+		},
+		{
+			name: "notified",
+			run: func(c *ApiKeyCache) {
+				c.Put("a", key, c.Generation())
+				c.Notify("a")
+			},
+			hash: "a",
+		},
+		{
+			name: "other notified",
+			run: func(c *ApiKeyCache) {
+				c.Put("a", key, c.Generation())
+				c.Notify("b")
+			},
+			hash: "a",
+		},
+		{
+			name: "reset",
+			run: func(c *ApiKeyCache) {
+				c.Put("a", key, c.Generation())
+				c.Reset()
+			},
+			hash: "a",
+		},
+		{
+			name: "evicted",
+			run: func(c *ApiKeyCache) {
+				c.Put("a", key, c.Generation())
+				c.Put("b", key, c.Generation())
+				c.Put("c", key, c.Generation())
+			},
+			hash: "c",
+		},
+		{
+			name: "notified during lookup",
+			run: func(c *ApiKeyCache) {
+				generation := c.Generation()
+				c.Notify("a")
+				c.Put("a", key, generation)
+			},
+			hash: "a",
+		},
+		{
+			name: "reset during lookup",
+			run: func(c *ApiKeyCache) {
+				generation := c.Generation()
+				c.Reset()
+				c.Put("a", key, generation)
+			},
+			hash: "a",
+		},
+		{
+			name: "disabled",
+			run: func(c *ApiKeyCache) {
+				c.ttl = 0
+				c.Put("a", key, c.Generation())
+			},
+			hash: "a",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cache := NewApiKeyCache(time.Minute, time.Second, 2)
+			cache.now = func() time.Time { return now }
+
+			tc.run(cache)
+
+			ret, ok := cache.Get(tc.hash)
+
+			test.Match(t, ret != nil, ok, len(cache.entries) <= cache.size)
+		})
+	}
+}
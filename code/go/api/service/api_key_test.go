@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
+
+	repository "toll/api/repository/mocks"
+	"toll/api/types"
+	"toll/internal/test"
+)
+
+func TestApiKeyCreate(t *testing.T) {
+	t.Parallel()
+
+	var stored *types.ApiKey
+
+	// Define mocked executions.
+	keys := repository.NewMockApiKeyRepository(t)
+	keys.EXPECT().
+		Create(t.Context(), mock.Anything).
+		Run(func(_ context.Context, key *types.ApiKey) {
+			stored = key
+		}).
+		Return(nil)
+
+	svc := &apiKeys{keys: keys}
+
+	plaintext, err := svc.Create(t.Context(), &types.ApiKey{
+		Description: "gantry 1",
+		Scopes:      types.Scopes{types.ScopeEventsWrite},
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+
+	test.Match(t,
+		err,
+		len(plaintext),
+		stored.KeyHash == hashApiKey(plaintext),
+		stored.Id != uuid.Nil,
+		stored.Description,
+		stored.Scopes,
+	)
+}
+
+func TestApiKeyRevoke_NotFound(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d")
+
+	// Define mocked executions.
+	keys := repository.NewMockApiKeyRepository(t)
+	keys.EXPECT().Revoke(t.Context(), id).Return(false, nil)
+
+	svc := &apiKeys{keys: keys}
+
+	err := svc.Revoke(t.Context(), id)
+
+	test.Match(t, errors.Is(err, ErrNotFound))
+}
+
+func TestValidateApiKey_Revoked(t *testing.T) {
+	t.Parallel()
+
+	revokedAt := time.Now().Add(-time.Hour)
+
+	// Define mocked executions.
+	keys := repository.NewMockApiKeyRepository(t)
+	keys.EXPECT().
+		Get(t.Context(), hashApiKey("secret")).
+		Return(&types.ApiKey{
+			Id:        uuid.MustParse("6f1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"),
+			ExpiresAt: time.Now().Add(time.Hour),
+			RevokedAt: &revokedAt,
+		}, nil)
+
+	svc := &auth{keys: keys}
+
+	key, err := svc.ValidateApiKey(t.Context(), "secret")
+
+	test.Match(t, key, errors.Is(err, ErrApiKeyRevoked))
+}
@@ -10,6 +10,7 @@
 	"github.com/pkg/errors"
 
 	"toll/api/repository"
+	"toll/api/types"
 
 	"toll/internal/database"
 	"toll/internal/errlog"
@@ -24,46 +25,83 @@
 
 	// ErrUserIdNotSet is returned when user id is missing in api key object.
 	ErrApiKeyUserIdNotSet = errors.New("missing user id in api key")
+
+	// ErrApiKeyRevoked is returned when api key is revoked.
+	ErrApiKeyRevoked = errors.New("api key is revoked")
 )
 
 type (
 	// AuthService interface with method definitions.
 	AuthService interface {
-		ValidateApiKey(ctx context.Context, key string) (*uuid.UUID, error)
+		ValidateApiKey(ctx context.Context, key string) (*types.ApiKey, error)
 	}
 
 	auth struct {
-		keys repository.ApiKeyRepository
+		keys  repository.ApiKeyRepository
+		cache *ApiKeyCache
 	}
 )
 
-// Auth func returns new AuthService with new background context.
-func Auth() AuthService {
+// Auth func returns new AuthService looking up keys through the cache.
+func Auth(cache *ApiKeyCache) AuthService {
 	db := database.Get()
 
 	return &auth{
-		keys: repository.ApiKey(db),
+		keys:  repository.ApiKey(db),
+		cache: cache,
 	}
 }
 
-func (svc *auth) ValidateApiKey(ctx context.Context, key string) (*uuid.UUID, error) {
-	h := sha256.New()
-	h.Write([]byte(key))
-
-	keyHash := base64.RawURLEncoding.EncodeToString(h.Sum(nil))
-
-	apiKey, err := svc.keys.Get(ctx, keyHash)
+// ValidateApiKey returns the active api key matching the plaintext key.
+func (svc *auth) ValidateApiKey(ctx context.Context, key string) (*types.ApiKey, error) {
+	apiKey, err := svc.lookup(ctx, hashApiKey(key))
 	if err != nil {
 		return nil, errlog.Error(err)
 	}
 
-	if apiKey == nil || apiKey.Id == uuid.Nil {
+	if apiKey == nil {
 		return nil, errlog.Error(ErrIncorrectApiKey)
 	}
 
+	if apiKey.IsRevoked() {
+		return nil, errlog.Error(ErrApiKeyRevoked)
+	}
+
 	if apiKey.ExpiresAt.Before(time.Now()) {
 		return nil, errlog.Error(ErrApiKeyExpired)
 	}
 
-	return &apiKey.Id, nil
+	return apiKey, nil
+}
+
+// lookup returns the key of the hash from the cache or the database, nil when unknown.
+// Revocation and expiry are not checked since they change without a lookup.
+func (svc *auth) lookup(ctx context.Context, hash string) (*types.ApiKey, error) {
+	if apiKey, ok := svc.cache.Get(hash); ok {
+		return apiKey, nil
+	}
+
+	// Generation is read before the database, so a change notified while the key
+	// is read keeps the possibly stale key out of the cache.
+	generation := svc.cache.Generation()
+
+	apiKey, err := svc.keys.Get(ctx, hash)
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	if apiKey != nil && apiKey.Id == uuid.Nil {
+		apiKey = nil
+	}
+
+	svc.cache.Put(hash, apiKey, generation)
+
+	return apiKey, nil
+}
+
+// hashApiKey returns stored hash of the plaintext api key.
+func hashApiKey(key string) string {
+	h := sha256.Sum256([]byte(key))
+
+	return base64.RawURLEncoding.EncodeToString(h[:])
 }
@@ -2,288 +2,707 @@
 
 import (
 	"context"
+	"hash/fnv"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"toll/api/calendar"
 	"toll/api/repository"
 	"toll/api/types"
 
 	"toll/internal/database"
+	"toll/internal/errlog"
 	log "toll/internal/log"
 )
 
-const bufferSize = 2000
 const workesCount = 5
 const workerBatchSizeLimit = 2000
 const workerTimeoutTrigger = 30 * time.Second
 
-type timeRangeFee struct {
-	startHour, startMinute int
-	endHour, endMinute     int
-	fee                    int
-}
-
-var holidays = map[string]struct{}{
-	"2025-01-01": {},
-	"2025-12-25": {},
-}
-
-// Toll fee prices hour list.
-// {startHr, startMin, endHr, endMin, price}.
-var tollFees = []timeRangeFee{
-	{6, 0, 6, 29, 8},
-	{6, 30, 6, 59, 13},
-	{7, 0, 7, 59, 18},
-	{8, 0, 8, 29, 13},
-	{8, 30, 14, 59, 8},
-	{15, 0, 15, 29, 13},
-	{15, 30, 16, 59, 18},
-	{17, 0, 17, 59, 13},
-	{18, 0, 18, 29, 8},
-}
+// Billing job queue retry settings.
+const jobLeaseDuration = 5 * time.Minute
+const jobMaxAttempts = 8
+const jobRetryBaseDelay = 30 * time.Second
+const jobRetryMaxDelay = time.Hour
 
-var maxDailyFee = 60
+// Billing queue backpressure settings.
+const queueDepthInterval = 5 * time.Second
+const backlogRetryAfter = workerTimeoutTrigger
 
 type (
 	// BillingService interface with method definitions.
 	BillingService interface {
-		TriggerFor(license string)
+		CheckBacklog() error
+		TriggerFor(ctx context.Context, license string, at time.Time) error
+		TriggerForEvents(ctx context.Context, events []*types.TollEvent) error
+		Rebill(ctx context.Context, at time.Time) (int, error)
+		Stop(ctx context.Context) error
+	}
+
+	// billingKey identifies daily fee of a license on a billing day.
+	billingKey struct {
+		license string
+		day     time.Time
 	}
 
 	billing struct {
 		log log.Logger
 
-		cancel      context.CancelFunc
-		wg          sync.WaitGroup
-		licenseCh   chan string
-		workerCount int
-
-		events repository.TollEventRepository
+		loc        *time.Location
+		calendar   *calendar.Calendar
+		exemptions calendar.DateExemptionPolicy
+
+		cancel     context.CancelFunc
+		wg         sync.WaitGroup
+		partitions []chan []*types.BillingJob
+		stopCh     chan struct{}
+		stopOnce   sync.Once
+		inFlight   atomic.Int64
+		queueDepth atomic.Int64
+		maxBacklog int64
+		maxStay    time.Duration
+
+		jobs          repository.BillingJobRepository
+		events        repository.TollEventRepository
+		tariffs       repository.TariffRepository
+		exemptionDays repository.ExemptionDayRepository
 	}
 )
 
 // BillingWorkers func returns new BillingService with workers started.
-func BillingWorkers(workerCount int, bufferSize int) BillingService {
+// Workers consume billing jobs persisted in the database, so pending work survives
+// restarts and is shared between API replicas. Jobs are partitioned between workers
+// by license plate, so each plate is always billed by the same worker.
+// Billing days, fee windows and holidays are evaluated in the provided location.
+// New triggers are rejected while more than maxBacklog jobs are pending, 0 disables the limit.
+func BillingWorkers(workerCount int, loc *time.Location, maxBacklog int, maxStay time.Duration) BillingService {
 	db := database.Get()
 	ctx, cancel := context.WithCancel(context.Background())
+	cal := calendar.New()
 
 	svc := &billing{
 		log: log.WithField(types.LogComponent, "billing"),
 
-		events:      repository.TollEvent(db),
-		licenseCh:   make(chan string, bufferSize),
-		workerCount: workerCount,
-		cancel:      cancel,
+		loc:        loc,
+		calendar:   cal,
+		exemptions: calendar.DefaultPolicy(cal),
+
+		jobs:          repository.BillingJob(db),
+		events:        repository.TollEvent(db),
+		tariffs:       repository.Tariff(db),
+		exemptionDays: repository.ExemptionDay(db),
+		partitions:    make([]chan []*types.BillingJob, workerCount),
+		maxBacklog:    int64(maxBacklog),
+		maxStay:       maxStay,
+		cancel:        cancel,
+		stopCh:        make(chan struct{}),
 	}
 
-	for i := 0; i < workerCount; i++ {
+	svc.wg.Add(1)
+
+	go svc.monitorQueue(ctx, queueDepthInterval)
+
+	for i := range svc.partitions {
+		svc.partitions[i] = make(chan []*types.BillingJob)
 		svc.wg.Add(1)
 
-		go svc.worker(ctx, i+1, workerBatchSizeLimit, workerTimeoutTrigger)
+		go svc.worker(ctx, i+1, svc.partitions[i])
 	}
 
+	svc.wg.Add(1)
+
+	go svc.dispatcher(ctx, workerBatchSizeLimit, workerTimeoutTrigger)
+
 	return svc
 }
 
-// TriggerFor sends a license to the processing channel.
-func (svc *billing) TriggerFor(license string) {
-	svc.licenseCh <- license
+// CheckBacklog returns BacklogError while the queue is over the limit. Events
+// should be checked before they are stored, so rejected events are not stored.
+func (svc *billing) CheckBacklog() error {
+	if depth := svc.queueDepth.Load(); svc.maxBacklog > 0 && depth >= svc.maxBacklog {
+		return &BacklogError{Depth: depth, RetryAfter: backlogRetryAfter}
+	}
+
+	return nil
 }
 
-func (svc *billing) billLicenses(ctx context.Context, licenses []string) error {
-	if len(licenses) == 0 {
+// TriggerFor enqueues billing job for the license on the billing day of the event time.
+// It does not wait for billing and enqueues the job whatever the backlog is, since
+// the event is already stored.
+func (svc *billing) TriggerFor(ctx context.Context, license string, at time.Time) error {
+	select {
+	case <-svc.stopCh:
+		return ErrBillingStopped
+	case <-ctx.Done():
+		return errlog.Error(ctx.Err())
+	default:
+	}
+
+	day, _ := svc.billingDay(at)
+
+	return svc.jobs.Enqueue(ctx, license, day)
+}
+
+// TriggerForEvents enqueues one billing job per license and billing day of the
+// not toll-free events.
+func (svc *billing) TriggerForEvents(ctx context.Context, events []*types.TollEvent) error {
+	seen := make(map[billingKey]struct{}, len(events))
+
+	for _, event := range events {
+		if event.IsTollFree() {
+			continue
+		}
+
+		day, _ := svc.billingDay(event.EventStart)
+
+		key := billingKey{license: event.LicensePlate, day: day}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+
+		if err := svc.TriggerFor(ctx, key.license, day); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Rebill enqueues billing jobs for every license with events on the billing day of t
+// and returns their number. Daily fees are recomputed from all events of the day,
+// so rebilling replaces fees without double counting.
+func (svc *billing) Rebill(ctx context.Context, at time.Time) (int, error) {
+	select {
+	case <-svc.stopCh:
+		return 0, ErrBillingStopped
+	default:
+	}
+
+	start, end := svc.billingDay(at)
+
+	licenses, err := svc.events.GetLicenses(ctx, start, end)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, license := range licenses {
+		if err := svc.jobs.Enqueue(ctx, license, start); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(licenses), nil
+}
+
+// Stop stops intake and claiming of new jobs and waits for workers to finish
+// in-flight batches. When ctx is done first, in-flight batches are cancelled and
+// their jobs are retried once the lease expires.
+func (svc *billing) Stop(ctx context.Context) error {
+	svc.stopOnce.Do(func() {
+		close(svc.stopCh)
+	})
+
+	done := make(chan struct{})
+
+	go func() {
+		svc.wg.Wait()
+		close(done)
+	}()
+
+	var err error
+
+	select {
+	case <-done:
+		svc.log.Info("billing workers drained")
+
+	case <-ctx.Done():
+		svc.log.Warnf("billing drain timed out with %d claimed jobs unprocessed", svc.inFlight.Load())
+
+		err = errlog.Error(ErrBillingDrainTimeout)
+	}
+
+	svc.cancel()
+	<-done
+
+	// Report work left in the queue for other replicas or the next start.
+	ctxCount, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	pending, cerr := svc.jobs.CountPending(ctxCount)
+	if cerr != nil {
+		svc.log.Errorf("error counting pending billing jobs: %v", cerr)
+	} else {
+		svc.log.Infof("billing stopped with %d pending jobs in queue", pending)
+	}
+
+	return err
+}
+
+// billBatch groups triggers by billing day and recomputes every affected day.
+// Returned map holds errors of the days that failed billing.
+func (svc *billing) billBatch(ctx context.Context, batch []billingKey) map[time.Time]error {
+	if len(batch) == 0 {
 		return nil
 	}
 
-	now := time.Now()
-	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// group licenses by billing day, repeated triggers of a license are billed once
+	seen := make(map[billingKey]struct{}, len(batch))
+	licensesByDay := make(map[time.Time][]string)
+
+	for _, key := range batch {
+		if _, ok := seen[key]; ok {
+			continue
+		}
 
-	// fetch ALL events for the day (all licenses)
-	events, err := svc.events.GetAll(ctx, startOfDay, licenses)
+		seen[key] = struct{}{}
+		licensesByDay[key.day] = append(licensesByDay[key.day], key.license)
+	}
+
+	failAll := func(err error) map[time.Time]error {
+		failed := make(map[time.Time]error, len(licensesByDay))
+		for day := range licensesByDay {
+			failed[day] = err
+		}
+
+		return failed
+	}
+
+	// Tariffs are loaded for every batch so price changes apply without redeploy.
+	tariffs, err := svc.tariffs.GetAll(ctx)
 	if err != nil {
-		return err
+		return failAll(err)
 	}
 
-	// group events by license plate
-	eventsByLicense := make(map[string][]*types.TollEvent)
-	for _, ev := range events {
-		eventsByLicense[ev.LicensePlate] = append(eventsByLicense[ev.LicensePlate], ev)
+	exemptionDays, err := svc.exemptionDays.GetAll(ctx)
+	if err != nil {
+		return failAll(err)
 	}
 
-	for _, license := range licenses {
-		levents := eventsByLicense[license]
+	exemptions := make([]time.Time, 0, len(exemptionDays))
+	for _, d := range exemptionDays {
+		exemptions = append(exemptions, d.Date)
+	}
+
+	svc.calendar.SetExemptions(exemptions...)
+
+	days := make([]time.Time, 0, len(licensesByDay))
+	for day := range licensesByDay {
+		days = append(days, day)
+	}
+
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Before(days[j])
+	})
+
+	// Failure of one day should not block billing of the others.
+	var failed map[time.Time]error
+
+	for _, day := range days {
+		if err := svc.billLicenses(ctx, tariffs, day, licensesByDay[day]); err != nil {
+			if failed == nil {
+				failed = make(map[time.Time]error)
+			}
+
+			failed[day] = err
+		}
+	}
+
+	return failed
+}
+
+// billLicenses recomputes daily fee of the licenses for the billing day.
+func (svc *billing) billLicenses(ctx context.Context, tariffs types.Tariffs, day time.Time, licenses []string) error {
+	if len(licenses) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	startOfDay, endOfDay := svc.billingDay(day)
+
+	if tariffs.At(startOfDay) == nil {
+		return errlog.Errorf("%w for %s", ErrNoTariff, startOfDay.Format(time.DateOnly))
+	}
+
+	var billed []types.DailyFee
+
+	eventCount := make(map[string]int, len(licenses))
+
+	// Events are flagged billed and daily fees written in one transaction.
+	err := svc.events.Bill(ctx, startOfDay, endOfDay, licenses, func(events []*types.TollEvent) []types.DailyFee {
+		// group events by license plate
+		eventsByLicense := make(map[string][]*types.TollEvent)
+		for _, ev := range events {
+			eventsByLicense[ev.LicensePlate] = append(eventsByLicense[ev.LicensePlate], ev)
+		}
+
+		billed = make([]types.DailyFee, 0, len(licenses))
 
-		totalFee := svc.calculateDailyFee(levents)
+		for _, license := range licenses {
+			levents := eventsByLicense[license]
+			eventCount[license] = len(levents)
 
-		// write fee row for this license
-		err := svc.events.UpdateDailyFee(
-			ctx,
-			types.DailyFee{
+			fee := types.DailyFee{
 				Date:         startOfDay,
 				LicensePlate: license,
-				Fee:          totalFee,
-			},
-		)
-		if err != nil {
-			svc.log.Errorf("Billing for %s failed: %v", license, err)
-			return err
+				Items:        svc.dailyFeeItems(tariffs, levents),
+			}
+
+			for i := range fee.Items {
+				fee.Items[i].Date = startOfDay
+				fee.Fee += fee.Items[i].Charged
+			}
+
+			billed = append(billed, fee)
 		}
 
+		return billed
+	})
+	if err != nil {
+		svc.log.Errorf("Billing of %d licenses on %s failed: %v", len(licenses), startOfDay.Format(time.DateOnly), err)
+
+		return err
+	}
+
+	for _, fee := range billed {
 		svc.log.Infof(
-			"Billing %s: %d events, total fee = %d SEK (took %s)",
-			license, len(levents), totalFee, time.Since(now),
+			"Billing %s on %s: %d events, total fee = %d SEK (took %s)",
+			fee.LicensePlate, startOfDay.Format(time.DateOnly), eventCount[fee.LicensePlate], fee.Fee, time.Since(now),
 		)
 	}
 
 	return nil
 }
 
-func IsTollFreeDate(t time.Time) bool {
-	weekday := t.Weekday()
-	if weekday == time.Saturday || weekday == time.Sunday {
-		return false
-	}
-
-	dateStr := t.Format("2006-01-02")
-	if _, exists := holidays[dateStr]; exists {
-		return true
-	}
+// billingDay returns start and end of the billing day containing t in the billing timezone.
+// Days with DST transitions are 23 or 25 hours long.
+func (svc *billing) billingDay(t time.Time) (time.Time, time.Time) {
+	local := t.In(svc.loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, svc.loc)
 
-	return false
+	return start, start.AddDate(0, 0, 1)
 }
 
-func priceForEvent(event *types.TollEvent) int {
-	if IsTollFreeDate(event.EventStart) || event.IsTollFree() {
+// priceForEvent returns fee for the event using tariff valid at the event start.
+// Date exemptions and fee windows are evaluated in the billing timezone.
+func (svc *billing) priceForEvent(tariffs types.Tariffs, event *types.TollEvent) int {
+	start := event.EventStart.In(svc.loc)
+
+	if svc.exemptions.IsExempt(start) || event.IsTollFree() {
 		return 0
 	}
 
-	hour := event.EventStart.Hour()
-	minute := event.EventStart.Minute()
-
-	for _, tf := range tollFees {
-		if (hour > tf.startHour || (hour == tf.startHour && minute >= tf.startMinute)) &&
-			(hour < tf.endHour || (hour == tf.endHour && minute <= tf.endMinute)) {
-			return tf.fee
-		}
+	tariff := tariffs.At(start)
+	if tariff == nil {
+		return 0
 	}
 
-	return 0
+	return tariff.FeeAt(start)
 }
 
-func (svc *billing) calculateDailyFee(events []*types.TollEvent) int {
+// dailyFeeItems splits events into one-hour windows charged with the highest fee in the
+// window. Events starting while the vehicle is still seen in the window, before a stop
+// of earlier window event, belong to the same stay and extend the window. Stays longer
+// than the max stay are cut to it. Windows over the daily cap of the tariff valid at
+// the first event are clipped.
+func (svc *billing) dailyFeeItems(tariffs types.Tariffs, events []*types.TollEvent) []types.DailyFeeItem {
 	if len(events) == 0 {
 		svc.log.Debug("no events; total fee = 0")
 
-		return 0
+		return nil
 	}
 
-	windowStart := events[0].EventStart
-	maxFeeInWindow := priceForEvent(events[0])
-
-	svc.log.Debugf("start window at %s with event start at %s fee=%d", windowStart, events[0].EventStart, maxFeeInWindow)
+	var (
+		items    []types.DailyFeeItem
+		lastSeen time.Time
+	)
+
+	for _, event := range events {
+		fee := svc.priceForEvent(tariffs, event)
+		priced := types.FeeItemEvent{
+			EventStart:  event.EventStart,
+			EventStop:   event.EventStop,
+			VehicleType: event.VehicleType,
+			Fee:         fee,
+		}
 
-	total := 0
+		if n := len(items); n > 0 &&
+			(event.EventStart.Sub(items[n-1].WindowStart) < time.Hour || !event.EventStart.After(lastSeen)) {
+			// Inside the same 1hr window or the same stay.
+			window := &items[n-1]
 
-	for _, event := range events[1:] {
-		fee := priceForEvent(event)
-		diff := event.EventStart.Sub(windowStart)
+			if seen := svc.lastSeen(event); seen.After(lastSeen) {
+				lastSeen = seen
+			}
+			window.Events = append(window.Events, priced)
 
-		if diff < time.Hour {
-			// Inside the same 1hr window.
-			if fee > maxFeeInWindow {
-				svc.log.Debugf("event with start at %s fee=%d replaces previous max fee=%d in same window", event.EventStart, fee, maxFeeInWindow)
+			if fee > window.Fee {
+				svc.log.Debugf("event with start at %s fee=%d replaces previous max fee=%d in same window", event.EventStart, fee, window.Fee)
 
-				maxFeeInWindow = fee
+				window.Fee = fee
 			} else {
-				svc.log.Debugf("event with start at %s fee=%d ignored windowStart=%s, maxFeeInWindow=%d", event.EventStart, fee, windowStart, maxFeeInWindow)
+				svc.log.Debugf("event with start at %s fee=%d ignored windowStart=%s, maxFeeInWindow=%d", event.EventStart, fee, window.WindowStart, window.Fee)
 			}
-		} else {
-			// Window ended add the max fee and start a new one.
-			svc.log.Debugf("closing window starting at %s: adding fee=%d", windowStart, maxFeeInWindow)
 
-			total += maxFeeInWindow
+			continue
+		}
 
-			svc.log.Debugf("new window start at %s with event start at %s fee=%d", windowStart, event.EventStart, fee)
+		svc.log.Debugf("new window start at %s fee=%d", event.EventStart, fee)
 
-			windowStart = event.EventStart
-			maxFeeInWindow = fee
-		}
+		lastSeen = svc.lastSeen(event)
+
+		items = append(items, types.DailyFeeItem{
+			LicensePlate: event.LicensePlate,
+			WindowStart:  event.EventStart,
+			Events:       types.FeeItemEvents{priced},
+			Fee:          fee,
+		})
 	}
 
-	svc.log.Debugf("closing final window starting at %s: adding fee=%d", windowStart, maxFeeInWindow)
+	// Apply daily maximum cap of the tariff valid at the first event of the day.
+	maxDailyFee := 0
+	if tariff := tariffs.At(events[0].EventStart); tariff != nil {
+		maxDailyFee = tariff.MaxDailyFee
+	}
 
-	total += maxFeeInWindow
+	total := 0
 
-	// Apply daily maximum cap.
-	if total > maxDailyFee {
-		svc.log.Debugf("total fee %d exceeds maxDailyFee=%d ; total fee = daily maximum cap", total, maxDailyFee)
+	for i := range items {
+		item := &items[i]
+		item.Charged = min(item.Fee, max(maxDailyFee-total, 0))
+		item.Capped = item.Charged < item.Fee
+		total += item.Charged
 
-		return maxDailyFee
+		if item.Capped {
+			svc.log.Debugf("window starting at %s fee=%d clipped to %d by maxDailyFee=%d", item.WindowStart, item.Fee, item.Charged, maxDailyFee)
+		}
 	}
 
-	svc.log.Debugf("total fee for day = %d", total)
+	return items
+}
+
+// lastSeen returns when the vehicle of the event was last seen, at most max stay
+// after the event start, so a far stop does not extend the window for the whole day.
+func (svc *billing) lastSeen(event *types.TollEvent) time.Time {
+	seen := event.LastSeen()
+
+	if svc.maxStay > 0 && seen.Sub(event.EventStart) > svc.maxStay {
+		return event.EventStart.Add(svc.maxStay)
+	}
 
-	return total
+	return seen
 }
 
-// worker listens to the license channel and triggers billing in batches.
-func (svc *billing) worker(ctx context.Context, workerID int, batchSize int, flushInterval time.Duration) {
+// monitorQueue periodically refreshes queue depth used for backpressure and metrics.
+func (svc *billing) monitorQueue(ctx context.Context, interval time.Duration) {
 	defer svc.wg.Done()
 
-	batch := make([]string, 0, batchSize)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 
-	flush := func() {
-		if len(batch) == 0 {
+	for {
+		svc.refreshQueueDepth(ctx)
+
+		select {
+		case <-svc.stopCh:
 			return
-		}
 
-		svc.log.Infof("Worker %d: flushing %d licenses", workerID, len(batch))
+		case <-ctx.Done():
+			return
 
-		if err := svc.billLicenses(ctx, batch); err != nil {
-			svc.log.Infof("Worker %d: error processing license %s: %v", workerID, batch, err)
+		case <-ticker.C:
 		}
+	}
+}
+
+// refreshQueueDepth loads number of pending jobs, last known depth is kept on error.
+func (svc *billing) refreshQueueDepth(ctx context.Context) {
+	depth, err := svc.jobs.CountPending(ctx)
+	if err != nil {
+		svc.log.Errorf("error counting pending billing jobs: %v", err)
 
-		batch = batch[:0]
+		return
 	}
 
-	timer := time.NewTimer(flushInterval)
+	svc.queueDepth.Store(int64(depth))
+	billingQueueDepth.Set(float64(depth))
+}
+
+// dispatcher claims billing jobs from the queue and hands them over to workers.
+// Queue is polled again right away while there are more jobs than fit in a batch.
+// Stopping the service ends the loop and lets workers finish handed over jobs.
+func (svc *billing) dispatcher(ctx context.Context, batchSize int, pollInterval time.Duration) {
+	defer svc.wg.Done()
+
+	defer func() {
+		for _, jobs := range svc.partitions {
+			close(jobs)
+		}
+	}()
+
+	timer := time.NewTimer(0)
 	defer timer.Stop()
 
 	for {
 		select {
-		case <-ctx.Done():
-			svc.log.Infof("Worker %d stopping due to context cancellation", workerID)
+		case <-svc.stopCh:
+			svc.log.Info("Dispatcher stopping")
 
-			flush()
+			return
+
+		case <-ctx.Done():
+			svc.log.Info("Dispatcher stopping due to context cancellation")
 
 			return
 
-		case license, ok := <-svc.licenseCh:
-			if !ok {
-				svc.log.Infof("Worker %d exiting, channel closed", workerID)
+		case <-timer.C:
+			claimed := svc.dispatch(ctx, batchSize)
+
+			if claimed >= batchSize {
+				timer.Reset(0)
+			} else {
+				timer.Reset(pollInterval)
+			}
+		}
+	}
+}
 
-				flush()
+// dispatch claims one batch of jobs and hands them over to workers owning their
+// license plates. It returns number of claimed jobs.
+func (svc *billing) dispatch(ctx context.Context, batchSize int) int {
+	jobs, err := svc.jobs.Claim(ctx, batchSize, jobLeaseDuration)
+	if err != nil {
+		svc.log.Errorf("error claiming billing jobs: %v", err)
 
-				return
+		return 0
+	}
+
+	if len(jobs) == 0 {
+		return 0
+	}
+
+	svc.inFlight.Add(int64(len(jobs)))
+
+	parts := make([][]*types.BillingJob, len(svc.partitions))
+	for _, job := range jobs {
+		i := partitionOf(job.LicensePlate, len(parts))
+		parts[i] = append(parts[i], job)
+	}
+
+	for i, part := range parts {
+		if len(part) == 0 {
+			continue
+		}
+
+		select {
+		case svc.partitions[i] <- part:
+
+		case <-ctx.Done():
+			// Jobs not handed over are claimed again when the lease expires.
+			for _, rest := range parts[i:] {
+				svc.inFlight.Add(-int64(len(rest)))
 			}
 
-			batch = append(batch, license)
+			return len(jobs)
+		}
+	}
+
+	return len(jobs)
+}
 
-			if len(batch) >= batchSize {
-				flush()
+// worker bills jobs handed over by the dispatcher until its partition is closed.
+func (svc *billing) worker(ctx context.Context, workerID int, partition <-chan []*types.BillingJob) {
+	defer svc.wg.Done()
 
-				if !timer.Stop() {
-					<-timer.C
-				}
+	for {
+		select {
+		case jobs, ok := <-partition:
+			if !ok {
+				svc.log.Infof("Worker %d stopping", workerID)
 
-				timer.Reset(flushInterval)
+				return
 			}
 
-		case <-timer.C:
-			// flush because of timeout
-			flush()
+			svc.processJobs(ctx, workerID, jobs)
+
+		case <-ctx.Done():
+			svc.log.Infof("Worker %d stopping due to context cancellation", workerID)
+
+			return
+		}
+	}
+}
 
-			timer.Reset(flushInterval)
+// processJobs bills claimed jobs. Failed jobs are retried with backoff until they
+// are dead-lettered.
+func (svc *billing) processJobs(ctx context.Context, workerID int, jobs []*types.BillingJob) {
+	defer svc.inFlight.Add(-int64(len(jobs)))
+
+	svc.log.Infof("Worker %d: flushing %d licenses", workerID, len(jobs))
+
+	batch := make([]billingKey, 0, len(jobs))
+	for _, job := range jobs {
+		batch = append(batch, svc.jobKey(job))
+	}
+
+	failed := svc.billBatch(ctx, batch)
+
+	done := make([]int64, 0, len(jobs))
+
+	for _, job := range jobs {
+		err, ok := failed[svc.jobKey(job).day]
+		if !ok {
+			done = append(done, job.Id)
+
+			continue
+		}
+
+		if job.Attempts >= jobMaxAttempts {
+			svc.log.Errorf("Worker %d: billing job %d for %s failed %d times, dead-lettering: %v", workerID, job.Id, job.LicensePlate, job.Attempts, err)
+
+			err = svc.jobs.DeadLetter(ctx, job.Id, err.Error())
+		} else {
+			svc.log.Warnf("Worker %d: billing job %d for %s failed, retrying: %v", workerID, job.Id, job.LicensePlate, err)
+
+			err = svc.jobs.Retry(ctx, job.Id, time.Now().Add(retryBackoff(job.Attempts)), err.Error())
+		}
+
+		if err != nil {
+			svc.log.Errorf("Worker %d: error updating billing job %d: %v", workerID, job.Id, err)
 		}
 	}
+
+	if err := svc.jobs.Complete(ctx, done); err != nil {
+		svc.log.Errorf("Worker %d: error completing %d billing jobs: %v", workerID, len(done), err)
+	}
+}
+
+// partitionOf returns index of the worker partition owning the license plate.
+func partitionOf(license string, partitions int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(license))
+
+	return int(h.Sum32() % uint32(partitions))
+}
+
+// jobKey returns billing key of the job with billing day in the billing timezone.
+func (svc *billing) jobKey(job *types.BillingJob) billingKey {
+	return billingKey{
+		license: job.LicensePlate,
+		day:     job.BillingDate.In(svc.loc),
+	}
+}
+
+// retryBackoff returns exponential delay before next attempt of a failed job.
+func retryBackoff(attempts int) time.Duration {
+	delay := jobRetryBaseDelay
+
+	for i := 1; i < attempts && delay < jobRetryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, jobRetryMaxDelay)
 }
@@ -1,13 +1,82 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	"toll/api/calendar"
+	repository "toll/api/repository/mocks"
 	"toll/api/types"
 	"toll/internal/log"
 	"toll/internal/test"
 )
 
+// testTariffs mirrors the seeded tariff with a price change from March 2025.
+var testTariffs = types.Tariffs{
+	{
+		Id:          1,
+		ValidFrom:   time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC),
+		ValidTo:     ptr(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)),
+		MaxDailyFee: 60,
+		Fees: []types.TariffFee{
+			{TariffId: 1, StartHour: 6, StartMinute: 0, EndHour: 6, EndMinute: 29, Fee: 8},
+			{TariffId: 1, StartHour: 6, StartMinute: 30, EndHour: 6, EndMinute: 59, Fee: 13},
+			{TariffId: 1, StartHour: 7, StartMinute: 0, EndHour: 7, EndMinute: 59, Fee: 18},
+			{TariffId: 1, StartHour: 8, StartMinute: 0, EndHour: 8, EndMinute: 29, Fee: 13},
+			{TariffId: 1, StartHour: 8, StartMinute: 30, EndHour: 14, EndMinute: 59, Fee: 8},
+			{TariffId: 1, StartHour: 15, StartMinute: 0, EndHour: 15, EndMinute: 29, Fee: 13},
+			{TariffId: 1, StartHour: 15, StartMinute: 30, EndHour: 16, EndMinute: 59, Fee: 18},
+			{TariffId: 1, StartHour: 17, StartMinute: 0, EndHour: 17, EndMinute: 59, Fee: 13},
+			{TariffId: 1, StartHour: 18, StartMinute: 0, EndHour: 18, EndMinute: 29, Fee: 8},
+		},
+	},
+	{
+		Id:          2,
+		ValidFrom:   time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+		MaxDailyFee: 50,
+		Fees: []types.TariffFee{
+			{TariffId: 2, StartHour: 6, StartMinute: 0, EndHour: 8, EndMinute: 59, Fee: 20},
+			{TariffId: 2, StartHour: 9, StartMinute: 0, EndHour: 18, EndMinute: 29, Fee: 10},
+		},
+	},
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// feeTotal returns total of daily fee items charged for the events.
+func feeTotal(svc *billing, tariffs types.Tariffs, events []*types.TollEvent) int {
+	total := 0
+	for _, item := range svc.dailyFeeItems(tariffs, events) {
+		total += item.Charged
+	}
+
+	return total
+}
+
+// billEvents returns Bill implementation computing fees from the events and
+// passing each of them to record.
+func billEvents(
+	events []*types.TollEvent,
+	record func(types.DailyFee),
+) func(context.Context, time.Time, time.Time, []string, func([]*types.TollEvent) []types.DailyFee) error {
+	return func(_ context.Context, _, _ time.Time, _ []string, fees func([]*types.TollEvent) []types.DailyFee) error {
+		for _, fee := range fees(events) {
+			record(fee)
+		}
+
+		return nil
+	}
+}
+
 func TestDeviceDataServiceGetConnectedDevices(t *testing.T) {
 	t.Parallel()
 
@@ -96,7 +165,25 @@ func TestDeviceDataServiceGetConnectedDevices(t *testing.T) {
 				{EventStart: time.Date(2025, 2, 3, 17, 0, 0, 0, time.UTC), VehicleType: types.Car}, // 17:00 - 13
 				{EventStart: time.Date(2025, 2, 3, 18, 1, 0, 0, time.UTC), VehicleType: types.Car}, // 18:01 - 0
 			},
-			maxDailyFee,
+			60,
+		},
+		{
+			"event priced with tariff valid at event start",
+			[]*types.TollEvent{
+				{EventStart: time.Date(2025, 3, 3, 7, 0, 0, 0, time.UTC), VehicleType: types.Car}, // 7:00 - 20
+			},
+			20,
+		},
+		{
+			"daily cap of tariff valid at event start",
+			[]*types.TollEvent{
+				{EventStart: time.Date(2025, 3, 3, 6, 0, 0, 0, time.UTC), VehicleType: types.Car},  // 20
+				{EventStart: time.Date(2025, 3, 3, 7, 1, 0, 0, time.UTC), VehicleType: types.Car},  // 20
+				{EventStart: time.Date(2025, 3, 3, 8, 2, 0, 0, time.UTC), VehicleType: types.Car},  // 20
+				{EventStart: time.Date(2025, 3, 3, 9, 3, 0, 0, time.UTC), VehicleType: types.Car},  // 10
+				{EventStart: time.Date(2025, 3, 3, 10, 4, 0, 0, time.UTC), VehicleType: types.Car}, // 10
+			},
+			50,
 		},
 	}
 
@@ -105,12 +192,777 @@ func TestDeviceDataServiceGetConnectedDevices(t *testing.T) {
 			t.Parallel()
 
 			svc := &billing{
-				log: log.Noop(),
+				log:        log.Noop(),
+				loc:        time.UTC,
+				exemptions: calendar.DefaultPolicy(calendar.New()),
+			}
+
+			totalFeeResult := feeTotal(svc, testTariffs, tc.events)
+
+			test.Match(t, totalFeeResult, tc.expectedFee)
+		})
+	}
+}
+
+func TestBillingTimezone(t *testing.T) {
+	t.Parallel()
+
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		events      []*types.TollEvent
+		expectedFee int
+	}{
+		{
+			"event sent in UTC",
+			[]*types.TollEvent{
+				{EventStart: time.Date(2025, 2, 3, 6, 0, 0, 0, time.UTC), VehicleType: types.Car}, // 7:00 CET - 18
+			},
+			18,
+		},
+		{
+			"event sent in CET",
+			[]*types.TollEvent{
+				{EventStart: time.Date(2025, 2, 3, 7, 0, 0, 0, stockholm), VehicleType: types.Car}, // 7:00 CET - 18
+			},
+			18,
+		},
+		{
+			"spring forward day",
+			[]*types.TollEvent{
+				{EventStart: time.Date(2025, 3, 30, 4, 30, 0, 0, time.UTC), VehicleType: types.Car}, // 6:30 CEST - 20
+				{EventStart: time.Date(2025, 3, 30, 7, 0, 0, 0, time.UTC), VehicleType: types.Car},  // 9:00 CEST - 10
+			},
+			30,
+		},
+		{
+			"fall back day",
+			[]*types.TollEvent{
+				{EventStart: time.Date(2025, 10, 26, 4, 30, 0, 0, time.UTC), VehicleType: types.Car}, // 5:30 CET - 0
+				{EventStart: time.Date(2025, 10, 26, 8, 0, 0, 0, time.UTC), VehicleType: types.Car},  // 9:00 CET - 10
+			},
+			10,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// DST transitions happen on Sundays, weekends are not exempted to keep them billed.
+			svc := &billing{
+				log:        log.Noop(),
+				loc:        stockholm,
+				exemptions: calendar.HolidayPolicy(calendar.New()),
 			}
 
-			totalFeeResult := svc.calculateDailyFee(tc.events)
+			totalFeeResult := feeTotal(svc, testTariffs, tc.events)
 
 			test.Match(t, totalFeeResult, tc.expectedFee)
 		})
 	}
 }
+
+func TestBillingDay(t *testing.T) {
+	t.Parallel()
+
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+	}{
+		{"regular day", time.Date(2025, 2, 3, 12, 0, 0, 0, time.UTC)},
+		{"utc event after local midnight", time.Date(2025, 2, 2, 23, 30, 0, 0, time.UTC)},
+		{"spring forward day", time.Date(2025, 3, 30, 12, 0, 0, 0, time.UTC)},
+		{"fall back day", time.Date(2025, 10, 26, 12, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := &billing{
+				log: log.Noop(),
+				loc: stockholm,
+			}
+
+			start, end := svc.billingDay(tc.at)
+
+			test.Match(t, start.Format(time.RFC3339), end.Format(time.RFC3339), end.Sub(start).String())
+		})
+	}
+}
+
+func TestDateExemptionPolicies(t *testing.T) {
+	t.Parallel()
+
+	cal := calendar.New()
+
+	policies := []struct {
+		name   string
+		policy calendar.DateExemptionPolicy
+	}{
+		{"weekend", calendar.WeekendPolicy()},
+		{"holiday", calendar.HolidayPolicy(cal)},
+		{"eve", calendar.EveOfHolidayPolicy(cal)},
+		{"july", calendar.MonthPolicy(time.July)},
+	}
+
+	days := []struct {
+		name string
+		date time.Time
+	}{
+		{"weekday", time.Date(2025, time.February, 3, 7, 0, 0, 0, time.UTC)},
+		{"saturday", time.Date(2025, time.February, 1, 7, 0, 0, 0, time.UTC)},
+		{"holiday", time.Date(2025, time.May, 1, 7, 0, 0, 0, time.UTC)},
+		{"eve of holiday", time.Date(2025, time.April, 30, 7, 0, 0, 0, time.UTC)},
+		{"july", time.Date(2025, time.July, 15, 7, 0, 0, 0, time.UTC)},
+		{"holiday on weekend", time.Date(2025, time.November, 1, 7, 0, 0, 0, time.UTC)},
+	}
+
+	// Run every combination of the policies.
+	for mask := 0; mask < 1<<len(policies); mask++ {
+		var (
+			names    []string
+			selected []calendar.DateExemptionPolicy
+		)
+
+		for i, p := range policies {
+			if mask&(1<<i) != 0 {
+				names = append(names, p.name)
+				selected = append(selected, p.policy)
+			}
+		}
+
+		name := "none"
+		if len(names) > 0 {
+			name = strings.Join(names, "+")
+		}
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			svc := &billing{
+				log:        log.Noop(),
+				loc:        time.UTC,
+				exemptions: calendar.AnyPolicy(selected...),
+			}
+
+			fees := make([]string, 0, len(days))
+			for _, d := range days {
+				fee := feeTotal(svc, testTariffs, []*types.TollEvent{
+					{EventStart: d.date, VehicleType: types.Car},
+				})
+
+				fees = append(fees, fmt.Sprintf("%s: %d", d.name, fee))
+			}
+
+			test.Match(t, fees)
+		})
+	}
+}
+
+func TestBillBatch_PastDays(t *testing.T) {
+	t.Parallel()
+
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tomorrow := time.Date(2025, 2, 5, 0, 0, 0, 0, stockholm)
+	today := time.Date(2025, 2, 4, 0, 0, 0, 0, stockholm)
+	yesterday := time.Date(2025, 2, 3, 0, 0, 0, 0, stockholm)
+
+	eventsYesterday := []*types.TollEvent{
+		{LicensePlate: "ABC123", EventStart: time.Date(2025, 2, 3, 6, 0, 0, 0, time.UTC), VehicleType: types.Car}, // 7:00 CET - 18
+	}
+	eventsToday := []*types.TollEvent{
+		{LicensePlate: "ABC123", EventStart: time.Date(2025, 2, 4, 6, 0, 0, 0, time.UTC), VehicleType: types.Car},  // 7:00 CET - 18
+		{LicensePlate: "XYZ789", EventStart: time.Date(2025, 2, 4, 14, 0, 0, 0, time.UTC), VehicleType: types.Car}, // 15:00 CET - 13
+	}
+
+	// Define mocked executions.
+	tariffs := repository.NewMockTariffRepository(t)
+	tariffs.EXPECT().GetAll(t.Context()).Return(testTariffs, nil)
+
+	exemptionDays := repository.NewMockExemptionDayRepository(t)
+	exemptionDays.EXPECT().GetAll(t.Context()).Return(nil, nil)
+
+	var fees []string
+
+	record := func(fee types.DailyFee) {
+		fees = append(fees, fmt.Sprintf("%s %s: %d", fee.Date.Format(time.RFC3339), fee.LicensePlate, fee.Fee))
+	}
+
+	events := repository.NewMockTollEventRepository(t)
+	events.EXPECT().
+		Bill(t.Context(), yesterday, today, []string{"ABC123"}, mock.Anything).
+		RunAndReturn(billEvents(eventsYesterday, record))
+	events.EXPECT().
+		Bill(t.Context(), today, tomorrow, []string{"ABC123", "XYZ789"}, mock.Anything).
+		RunAndReturn(billEvents(eventsToday, record))
+
+	cal := calendar.New()
+
+	svc := &billing{
+		log:        log.Noop(),
+		loc:        stockholm,
+		calendar:   cal,
+		exemptions: calendar.HolidayPolicy(cal),
+
+		events:        events,
+		tariffs:       tariffs,
+		exemptionDays: exemptionDays,
+	}
+
+	// Late event for yesterday arrives between events for today.
+	batch := []billingKey{
+		{license: "ABC123", day: today},
+		{license: "ABC123", day: yesterday},
+		{license: "XYZ789", day: today},
+	}
+
+	failed := svc.billBatch(t.Context(), batch)
+
+	test.Match(t, fees, failed)
+}
+
+func TestProcessJobs(t *testing.T) {
+	t.Parallel()
+
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feb3 := time.Date(2025, 2, 3, 0, 0, 0, 0, stockholm)
+	feb4 := time.Date(2025, 2, 4, 0, 0, 0, 0, stockholm)
+
+	// Billing dates are scanned from the database in UTC.
+	claimed := []*types.BillingJob{
+		{Id: 1, LicensePlate: "ABC123", BillingDate: feb3.UTC(), Attempts: 1},
+		{Id: 2, LicensePlate: "XYZ789", BillingDate: feb4.UTC(), Attempts: 1},
+		{Id: 3, LicensePlate: "DEF456", BillingDate: feb4.UTC(), Attempts: jobMaxAttempts},
+	}
+
+	var calls []string
+
+	// Define mocked executions.
+	jobs := repository.NewMockBillingJobRepository(t)
+	jobs.EXPECT().
+		Retry(t.Context(), int64(2), mock.Anything, mock.Anything).
+		Run(func(_ context.Context, id int64, _ time.Time, reason string) {
+			calls = append(calls, fmt.Sprintf("retry %d: %s", id, reason))
+		}).
+		Return(nil)
+	jobs.EXPECT().
+		DeadLetter(t.Context(), int64(3), mock.Anything).
+		Run(func(_ context.Context, id int64, reason string) {
+			calls = append(calls, fmt.Sprintf("dead %d: %s", id, reason))
+		}).
+		Return(nil)
+	jobs.EXPECT().
+		Complete(t.Context(), []int64{1}).
+		Run(func(_ context.Context, ids []int64) {
+			calls = append(calls, fmt.Sprintf("complete %v", ids))
+		}).
+		Return(nil)
+
+	tariffs := repository.NewMockTariffRepository(t)
+	tariffs.EXPECT().GetAll(t.Context()).Return(testTariffs, nil)
+
+	exemptionDays := repository.NewMockExemptionDayRepository(t)
+	exemptionDays.EXPECT().GetAll(t.Context()).Return(nil, nil)
+
+	events := repository.NewMockTollEventRepository(t)
+	events.EXPECT().
+		Bill(t.Context(), feb3, feb4, []string{"ABC123"}, mock.Anything).
+		RunAndReturn(billEvents(nil, func(types.DailyFee) {}))
+	events.EXPECT().
+		Bill(t.Context(), feb4, feb4.AddDate(0, 0, 1), []string{"XYZ789", "DEF456"}, mock.Anything).
+		Return(errors.New("test error"))
+
+	cal := calendar.New()
+
+	svc := &billing{
+		log:        log.Noop(),
+		loc:        stockholm,
+		calendar:   cal,
+		exemptions: calendar.HolidayPolicy(cal),
+
+		jobs:          jobs,
+		events:        events,
+		tariffs:       tariffs,
+		exemptionDays: exemptionDays,
+	}
+
+	svc.processJobs(t.Context(), 1, claimed)
+
+	test.Match(t, calls)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	t.Parallel()
+
+	delays := make([]string, 0, jobMaxAttempts)
+	for attempts := 1; attempts <= jobMaxAttempts; attempts++ {
+		delays = append(delays, retryBackoff(attempts).String())
+	}
+
+	test.Match(t, delays)
+}
+
+func TestBillingStop(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		claim func(ctx context.Context, limit int, lease time.Duration) ([]*types.BillingJob, error)
+	}{
+		{
+			"idle workers drain",
+			func(ctx context.Context, limit int, lease time.Duration) ([]*types.BillingJob, error) {
+				return nil, nil
+			},
+		},
+		{
+			"in-flight batch exceeds drain timeout",
+			func(ctx context.Context, limit int, lease time.Duration) ([]*types.BillingJob, error) {
+				// Blocks until in-flight processing is cancelled.
+				<-ctx.Done()
+
+				return nil, ctx.Err()
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			jobs := repository.NewMockBillingJobRepository(t)
+			jobs.EXPECT().Claim(mock.Anything, 10, jobLeaseDuration).RunAndReturn(tc.claim)
+			jobs.EXPECT().CountPending(mock.Anything).Return(3, nil)
+
+			ctx, cancel := context.WithCancel(context.Background())
+
+			svc := &billing{
+				log:        log.Noop(),
+				loc:        time.UTC,
+				jobs:       jobs,
+				partitions: []chan []*types.BillingJob{make(chan []*types.BillingJob)},
+				cancel:     cancel,
+				stopCh:     make(chan struct{}),
+			}
+
+			svc.wg.Add(2)
+
+			go svc.worker(ctx, 1, svc.partitions[0])
+			go svc.dispatcher(ctx, 10, time.Hour)
+
+			// Give the worker time to claim the first batch.
+			time.Sleep(10 * time.Millisecond)
+
+			ctxStop, cancelStop := context.WithTimeout(t.Context(), 50*time.Millisecond)
+			defer cancelStop()
+
+			err := svc.Stop(ctxStop)
+
+			// Intake is closed after stop.
+			errTrigger := svc.TriggerFor(t.Context(), "ABC123", time.Now())
+
+			test.Match(t, errors.Is(err, ErrBillingDrainTimeout), errors.Is(errTrigger, ErrBillingStopped))
+		})
+	}
+}
+
+func TestCheckBacklog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		maxBacklog int64
+		depth      int
+	}{
+		{"below limit", 10, 9},
+		{"at limit", 10, 10},
+		{"limit disabled", 0, 1000},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Define mocked executions.
+			jobs := repository.NewMockBillingJobRepository(t)
+			jobs.EXPECT().CountPending(mock.Anything).Return(tc.depth, nil)
+
+			svc := &billing{
+				log:        log.Noop(),
+				loc:        time.UTC,
+				jobs:       jobs,
+				maxBacklog: tc.maxBacklog,
+				stopCh:     make(chan struct{}),
+			}
+
+			svc.refreshQueueDepth(t.Context())
+
+			err := svc.CheckBacklog()
+
+			var backlog *BacklogError
+
+			errors.As(err, &backlog)
+
+			test.Match(t, errors.Is(err, ErrBillingBacklog), backlog)
+		})
+	}
+}
+
+func TestTriggerForBacklog(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2025, time.March, 3, 7, 15, 0, 0, time.UTC)
+	day := time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC)
+
+	// Define mocked executions.
+	jobs := repository.NewMockBillingJobRepository(t)
+	jobs.EXPECT().CountPending(mock.Anything).Return(10, nil)
+	jobs.EXPECT().Enqueue(mock.Anything, "ABC123", day).Return(nil)
+
+	svc := &billing{
+		log:        log.Noop(),
+		loc:        time.UTC,
+		jobs:       jobs,
+		maxBacklog: 10,
+		stopCh:     make(chan struct{}),
+	}
+
+	svc.refreshQueueDepth(t.Context())
+
+	// Stored event is enqueued even when the queue is over the limit.
+	err := svc.TriggerFor(t.Context(), "ABC123", at)
+
+	test.Match(t, err)
+}
+
+func TestTriggerForCancelled(t *testing.T) {
+	t.Parallel()
+
+	svc := &billing{
+		log:    log.Noop(),
+		loc:    time.UTC,
+		jobs:   repository.NewMockBillingJobRepository(t),
+		stopCh: make(chan struct{}),
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	err := svc.TriggerFor(ctx, "ABC123", time.Now())
+
+	test.Match(t, errors.Is(err, context.Canceled))
+}
+
+func TestPartitionOf(t *testing.T) {
+	t.Parallel()
+
+	plates := []string{"ABC123", "XYZ789", "DEF456", "GHI012", "JKL345", "ABC123"}
+
+	partitions := make(map[string]int, len(plates))
+	for _, plate := range plates {
+		partitions[plate] = partitionOf(plate, workesCount)
+	}
+
+	test.Match(t, partitions)
+}
+
+func TestBillingWorkersDuplicatePlates(t *testing.T) {
+	t.Parallel()
+
+	const workers = 3
+
+	day := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+	plates := []string{"ABC123", "XYZ789", "DEF456", "GHI012", "JKL345"}
+
+	// Every plate is triggered several times, e.g. by passing multiple gantries.
+	claimed := make([]*types.BillingJob, 0, len(plates)*4)
+	for i := 0; i < 4; i++ {
+		for _, plate := range plates {
+			claimed = append(claimed, &types.BillingJob{
+				Id:           int64(len(claimed) + 1),
+				LicensePlate: plate,
+				BillingDate:  day,
+				Attempts:     1,
+			})
+		}
+	}
+
+	var (
+		mu        sync.Mutex
+		active    = make(map[string]bool)
+		overlaps  []string
+		fees      = make(map[string]int)
+		completed int
+	)
+
+	claimedCh := make(chan struct{})
+
+	// Define mocked executions.
+	jobs := repository.NewMockBillingJobRepository(t)
+	jobs.EXPECT().
+		Claim(mock.Anything, 100, jobLeaseDuration).
+		RunAndReturn(func(context.Context, int, time.Duration) ([]*types.BillingJob, error) {
+			close(claimedCh)
+
+			return claimed, nil
+		}).
+		Once()
+	jobs.EXPECT().
+		Complete(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, ids []int64) {
+			mu.Lock()
+			completed += len(ids)
+			mu.Unlock()
+		}).
+		Return(nil)
+	jobs.EXPECT().CountPending(mock.Anything).Return(0, nil)
+
+	tariffs := repository.NewMockTariffRepository(t)
+	tariffs.EXPECT().GetAll(mock.Anything).Return(testTariffs, nil)
+
+	exemptionDays := repository.NewMockExemptionDayRepository(t)
+	exemptionDays.EXPECT().GetAll(mock.Anything).Return(nil, nil)
+
+	events := repository.NewMockTollEventRepository(t)
+	events.EXPECT().
+		Bill(mock.Anything, day, day.AddDate(0, 0, 1), mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, _, _ time.Time, licenses []string, _ func([]*types.TollEvent) []types.DailyFee) error {
+			mu.Lock()
+			for _, license := range licenses {
+				if active[license] {
+					overlaps = append(overlaps, license)
+				}
+
+				active[license] = true
+				fees[license]++
+			}
+			mu.Unlock()
+
+			// Widen the window for concurrent billing of the same plate.
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			for _, license := range licenses {
+				active[license] = false
+			}
+			mu.Unlock()
+
+			return nil
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cal := calendar.New()
+
+	svc := &billing{
+		log:        log.Noop(),
+		loc:        time.UTC,
+		calendar:   cal,
+		exemptions: calendar.DefaultPolicy(cal),
+
+		jobs:          jobs,
+		events:        events,
+		tariffs:       tariffs,
+		exemptionDays: exemptionDays,
+		partitions:    make([]chan []*types.BillingJob, workers),
+		cancel:        cancel,
+		stopCh:        make(chan struct{}),
+	}
+
+	for i := range svc.partitions {
+		svc.partitions[i] = make(chan []*types.BillingJob)
+		svc.wg.Add(1)
+
+		go svc.worker(ctx, i+1, svc.partitions[i])
+	}
+
+	svc.wg.Add(1)
+
+	go svc.dispatcher(ctx, 100, time.Hour)
+
+	<-claimedCh
+
+	err := svc.Stop(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.Match(t, fees, overlaps, completed, svc.inFlight.Load())
+}
+
+func TestRebill(t *testing.T) {
+	t.Parallel()
+
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2025, 2, 3, 0, 0, 0, 0, stockholm)
+
+	var enqueued []string
+
+	// Define mocked executions.
+	events := repository.NewMockTollEventRepository(t)
+	events.EXPECT().GetLicenses(t.Context(), day, day.AddDate(0, 0, 1)).Return([]string{"ABC123", "XYZ789"}, nil)
+
+	jobs := repository.NewMockBillingJobRepository(t)
+	jobs.EXPECT().
+		Enqueue(t.Context(), mock.Anything, day).
+		Run(func(_ context.Context, license string, _ time.Time) {
+			enqueued = append(enqueued, license)
+		}).
+		Return(nil)
+
+	svc := &billing{
+		log:    log.Noop(),
+		loc:    stockholm,
+		jobs:   jobs,
+		events: events,
+		stopCh: make(chan struct{}),
+	}
+
+	// Late evening UTC event is on the next billing day in Stockholm.
+	count, err := svc.Rebill(t.Context(), time.Date(2025, 2, 2, 23, 30, 0, 0, time.UTC))
+
+	test.Match(t, count, err, enqueued)
+}
+
+func TestDailyFeeItems(t *testing.T) {
+	t.Parallel()
+
+	events := []*types.TollEvent{
+		{LicensePlate: "ABC123", EventStart: time.Date(2025, 2, 3, 6, 0, 0, 0, time.UTC), VehicleType: types.Car},   // 8
+		{LicensePlate: "ABC123", EventStart: time.Date(2025, 2, 3, 6, 40, 0, 0, time.UTC), VehicleType: types.Car},  // 13, replaces 8
+		{LicensePlate: "ABC123", EventStart: time.Date(2025, 2, 3, 7, 31, 0, 0, time.UTC), VehicleType: types.Car},  // 18
+		{LicensePlate: "ABC123", EventStart: time.Date(2025, 2, 3, 15, 0, 0, 0, time.UTC), VehicleType: types.Car},  // 13
+		{LicensePlate: "ABC123", EventStart: time.Date(2025, 2, 3, 16, 0, 0, 0, time.UTC), VehicleType: types.Car},  // 18, clipped to 16
+		{LicensePlate: "ABC123", EventStart: time.Date(2025, 2, 3, 17, 0, 0, 0, time.UTC), VehicleType: types.Car},  // 13, clipped to 0
+		{LicensePlate: "ABC123", EventStart: time.Date(2025, 2, 3, 17, 10, 0, 0, time.UTC), VehicleType: types.Van}, // 13, same window
+	}
+
+	svc := &billing{
+		log:        log.Noop(),
+		loc:        time.UTC,
+		exemptions: calendar.DefaultPolicy(calendar.New()),
+	}
+
+	items := svc.dailyFeeItems(testTariffs, events)
+
+	test.Match(t, items, feeTotal(svc, testTariffs, events))
+}
+
+func TestDailyFeeItemsDwell(t *testing.T) {
+	t.Parallel()
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 2, 3, hour, minute, 0, 0, time.UTC)
+	}
+
+	stop := at(9, 30)
+
+	events := []*types.TollEvent{
+		{LicensePlate: "ABC123", EventStart: at(7, 0), EventStop: &stop, VehicleType: types.Car}, // 18, stays until 9:30
+		{LicensePlate: "ABC123", EventStart: at(8, 30), VehicleType: types.Car},                  // 8, same stay
+		{LicensePlate: "ABC123", EventStart: at(9, 30), VehicleType: types.Car},                  // 8, same stay
+		{LicensePlate: "ABC123", EventStart: at(9, 40), VehicleType: types.Car},                  // 8, new window
+	}
+
+	svc := &billing{
+		log:        log.Noop(),
+		loc:        time.UTC,
+		exemptions: calendar.DefaultPolicy(calendar.New()),
+	}
+
+	items := svc.dailyFeeItems(testTariffs, events)
+
+	test.Match(t, items, feeTotal(svc, testTariffs, events))
+}
+
+func TestDailyFeeItemsMaxStay(t *testing.T) {
+	t.Parallel()
+
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 2, 3, hour, minute, 0, 0, time.UTC)
+	}
+
+	stop := at(23, 0)
+
+	events := []*types.TollEvent{
+		{LicensePlate: "ABC123", EventStart: at(7, 0), EventStop: &stop, VehicleType: types.Car}, // 18, stay cut at 9:00
+		{LicensePlate: "ABC123", EventStart: at(8, 30), VehicleType: types.Car},                  // 8, same stay
+		{LicensePlate: "ABC123", EventStart: at(15, 30), VehicleType: types.Car},                 // 18, new window
+	}
+
+	svc := &billing{
+		log:        log.Noop(),
+		loc:        time.UTC,
+		exemptions: calendar.DefaultPolicy(calendar.New()),
+		maxStay:    2 * time.Hour,
+	}
+
+	items := svc.dailyFeeItems(testTariffs, events)
+
+	test.Match(t, items, feeTotal(svc, testTariffs, events))
+}
+
+func TestTriggerForEvents(t *testing.T) {
+	t.Parallel()
+
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feb3 := time.Date(2025, 2, 3, 0, 0, 0, 0, stockholm)
+	feb4 := time.Date(2025, 2, 4, 0, 0, 0, 0, stockholm)
+
+	batch := []*types.TollEvent{
+		{LicensePlate: "ABC123", EventStart: feb3.Add(7 * time.Hour), VehicleType: types.Car},
+		{LicensePlate: "ABC123", EventStart: feb3.Add(8 * time.Hour), VehicleType: types.Car},
+		{LicensePlate: "ABC123", EventStart: feb4.Add(7 * time.Hour), VehicleType: types.Car},
+		{LicensePlate: "XYZ789", EventStart: feb3.Add(7 * time.Hour), VehicleType: types.Car},
+		{LicensePlate: "XYZ789", EventStart: feb3.Add(9 * time.Hour), VehicleType: types.Car},
+		{LicensePlate: "TRC001", EventStart: feb3.Add(7 * time.Hour), VehicleType: types.Tractor},
+	}
+
+	var enqueued []string
+
+	// Define mocked executions.
+	jobs := repository.NewMockBillingJobRepository(t)
+	jobs.EXPECT().
+		Enqueue(t.Context(), mock.Anything, mock.Anything).
+		Run(func(_ context.Context, license string, day time.Time) {
+			enqueued = append(enqueued, license+" "+day.Format(time.DateOnly))
+		}).
+		Return(nil)
+
+	svc := &billing{
+		log:    log.Noop(),
+		loc:    stockholm,
+		jobs:   jobs,
+		stopCh: make(chan struct{}),
+	}
+
+	err = svc.TriggerForEvents(t.Context(), batch)
+
+	test.Match(t, enqueued, err)
+}
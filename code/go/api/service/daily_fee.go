@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"toll/internal/database"
+
+	"toll/api/repository"
+	"toll/api/types"
+)
+
+type (
+	// DailyFeeService interface with method definitions.
+	DailyFeeService interface {
+		GetForLicense(ctx context.Context, license string, filter types.DailyFeeFilter) (*types.DailyFeePage, error)
+	}
+
+	dailyFee struct {
+		loc *time.Location
+
+		fees repository.DailyFeeRepository
+	}
+)
+
+// DailyFee func returns new DailyFeeService with billing days in the provided location.
+func DailyFee(loc *time.Location) DailyFeeService {
+	db := database.Get()
+
+	return &dailyFee{
+		loc:  loc,
+		fees: repository.DailyFee(db),
+	}
+}
+
+// GetForLicense returns page of daily fees of the license plate with dates in the
+// billing timezone. ErrNotFound is returned when the plate has never been billed.
+func (svc *dailyFee) GetForLicense(ctx context.Context, license string, filter types.DailyFeeFilter) (*types.DailyFeePage, error) {
+	var from, to *time.Time
+
+	if filter.From != nil {
+		start := svc.day(*filter.From)
+		from = &start
+	}
+
+	if filter.To != nil {
+		end := svc.day(*filter.To).AddDate(0, 0, 1)
+		to = &end
+	}
+
+	// One extra fee tells if there is a next page.
+	fees, err := svc.fees.GetForLicense(ctx, license, from, to, filter.Limit+1, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fees) == 0 {
+		exists, err := svc.fees.Exists(ctx, license)
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			return nil, ErrNotFound
+		}
+	}
+
+	page := &types.DailyFeePage{
+		Fees:    fees,
+		HasMore: len(fees) > filter.Limit,
+	}
+
+	if page.HasMore {
+		page.Fees = fees[:filter.Limit]
+	}
+
+	for _, fee := range page.Fees {
+		fee.Date = fee.Date.In(svc.loc)
+	}
+
+	return page, nil
+}
+
+// day returns start of the billing day with the calendar date of t.
+func (svc *dailyFee) day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, svc.loc)
+}
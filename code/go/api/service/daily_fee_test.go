@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	repository "toll/api/repository/mocks"
+	"toll/api/types"
+	"toll/internal/test"
+)
+
+func TestDailyFeeGetForLicense(t *testing.T) {
+	t.Parallel()
+
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feb3 := time.Date(2025, 2, 3, 0, 0, 0, 0, stockholm)
+	feb4 := time.Date(2025, 2, 4, 0, 0, 0, 0, stockholm)
+	feb5 := time.Date(2025, 2, 5, 0, 0, 0, 0, stockholm)
+
+	// Query dates are parsed in UTC.
+	from := time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 4, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		limit  int
+		stored []*types.DailyFee
+	}{
+		{
+			"last page",
+			2,
+			[]*types.DailyFee{
+				{Date: feb3.UTC(), LicensePlate: "ABC123", Fee: 18},
+				{Date: feb4.UTC(), LicensePlate: "ABC123", Fee: 26},
+			},
+		},
+		{
+			"more pages",
+			1,
+			[]*types.DailyFee{
+				{Date: feb3.UTC(), LicensePlate: "ABC123", Fee: 18},
+				{Date: feb4.UTC(), LicensePlate: "ABC123", Fee: 26},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Define mocked executions.
+			fees := repository.NewMockDailyFeeRepository(t)
+			fees.EXPECT().GetForLicense(t.Context(), "ABC123", &feb3, &feb5, tc.limit+1, 0).Return(tc.stored, nil)
+
+			svc := &dailyFee{loc: stockholm, fees: fees}
+
+			page, err := svc.GetForLicense(t.Context(), "ABC123", types.DailyFeeFilter{
+				From:  &from,
+				To:    &to,
+				Limit: tc.limit,
+			})
+
+			dates := make([]string, 0, len(page.Fees))
+			for _, fee := range page.Fees {
+				dates = append(dates, fee.Date.Format(time.DateOnly))
+			}
+
+			test.Match(t, dates, page.HasMore, err)
+		})
+	}
+}
+
+func TestDailyFeeGetForLicense_NotFound(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		exists bool
+	}{
+		{"unknown plate", false},
+		{"no fees in range", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Define mocked executions.
+			fees := repository.NewMockDailyFeeRepository(t)
+			fees.EXPECT().GetForLicense(t.Context(), "ABC123", (*time.Time)(nil), (*time.Time)(nil), 11, 0).Return(nil, nil)
+			fees.EXPECT().Exists(t.Context(), "ABC123").Return(tc.exists, nil)
+
+			svc := &dailyFee{loc: time.UTC, fees: fees}
+
+			page, err := svc.GetForLicense(t.Context(), "ABC123", types.DailyFeeFilter{Limit: 10})
+
+			test.Match(t, page, errors.Is(err, ErrNotFound))
+		})
+	}
+}
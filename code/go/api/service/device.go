@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"toll/api/repository"
+	"toll/api/types"
+
+	"toll/internal/database"
+	"toll/internal/errlog"
+)
+
+var (
+	// ErrUnknownDevice is returned when device is not found in the database.
+	ErrUnknownDevice = errors.New("unknown device")
+
+	// ErrDeviceRevoked is returned when device is revoked.
+	ErrDeviceRevoked = errors.New("device is revoked")
+)
+
+type (
+	// DeviceService interface with method definitions.
+	DeviceService interface {
+		GetActive(ctx context.Context, id uuid.UUID) (*types.Device, error)
+		GetActiveByCertificate(ctx context.Context, spki, subject string) (*types.Device, error)
+		ClaimNonce(ctx context.Context, id uuid.UUID, nonce string, expires, now time.Time) (bool, error)
+	}
+
+	devices struct {
+		devices repository.DeviceRepository
+	}
+)
+
+// Device func returns new DeviceService reading devices from the database.
+func Device() DeviceService {
+	db := database.Get()
+
+	return &devices{
+		devices: repository.Device(db),
+	}
+}
+
+// GetActive returns the device with the id unless it is unknown or revoked.
+func (svc *devices) GetActive(ctx context.Context, id uuid.UUID) (*types.Device, error) {
+	device, err := svc.devices.Get(ctx, id)
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	return activeDevice(device)
+}
+
+// GetActiveByCertificate returns the device registered with the client certificate
+// unless it is unknown or revoked.
+func (svc *devices) GetActiveByCertificate(ctx context.Context, spki, subject string) (*types.Device, error) {
+	device, err := svc.devices.GetByCertificate(ctx, spki, subject)
+	if err != nil {
+		return nil, errlog.Error(err)
+	}
+
+	return activeDevice(device)
+}
+
+// ClaimNonce records the signature nonce of the device for all replicas, it returns
+// false when the nonce was already used before it expired.
+func (svc *devices) ClaimNonce(ctx context.Context, id uuid.UUID, nonce string, expires, now time.Time) (bool, error) {
+	claimed, err := svc.devices.ClaimNonce(ctx, id, nonce, expires, now)
+	if err != nil {
+		return false, errlog.Error(err)
+	}
+
+	return claimed, nil
+}
+
+// activeDevice returns the device when it is known and not revoked.
+func activeDevice(device *types.Device) (*types.Device, error) {
+	if device == nil {
+		return nil, errlog.Error(ErrUnknownDevice)
+	}
+
+	if device.IsRevoked() {
+		return nil, errlog.Error(ErrDeviceRevoked)
+	}
+
+	return device, nil
+}
@@ -1,6 +1,9 @@
 package service
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/pkg/errors"
 )
 
@@ -10,4 +13,35 @@
 
 	// ErrNoPermissions is returned when there is no permission for entity.
 	ErrNoPermissions = errors.New("no permissions")
+
+	// ErrNotFound is returned when requested entity does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrNoTariff is returned when there is no tariff valid for the billing date.
+	ErrNoTariff = errors.New("no valid tariff")
+
+	// ErrBillingStopped is returned when billing is triggered after shutdown started.
+	ErrBillingStopped = errors.New("billing is stopped")
+
+	// ErrBillingDrainTimeout is returned when billing workers do not finish in time on shutdown.
+	ErrBillingDrainTimeout = errors.New("billing drain timed out")
+
+	// ErrBillingBacklog is matched by BacklogError when billing queue is over the limit.
+	ErrBillingBacklog = errors.New("billing queue is backlogged")
 )
+
+// BacklogError is returned when billing queue has too many pending jobs to accept more.
+type BacklogError struct {
+	Depth      int64
+	RetryAfter time.Duration
+}
+
+// Error returns string representation of the error.
+func (e *BacklogError) Error() string {
+	return fmt.Sprintf("%s: %d pending jobs, retry after %s", ErrBillingBacklog, e.Depth, e.RetryAfter)
+}
+
+// Is reports BacklogError as ErrBillingBacklog.
+func (e *BacklogError) Is(target error) bool {
+	return target == ErrBillingBacklog
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "toll"
+
+var billingQueueDepth = promauto.NewGauge(
+	prometheus.GaugeOpts{
+		Name: prometheus.BuildFQName(namespace, "billing", "queue_depth"),
+		Help: "Number of pending billing jobs, including the claimed ones.",
+	},
+)
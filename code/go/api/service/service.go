@@ -2,21 +2,40 @@
 
 import (
 	"sync"
+
+	"toll/api/config"
 )
 
 var (
 	once sync.Once
 
+	KeyCache      *ApiKeyCache
 	Authorization AuthService
+	Devices       DeviceService
 	TollEvents    TollEventService
 	Billing       BillingService
+	DailyFees     DailyFeeService
+	ApiKeys       ApiKeyService
 )
 
 // Init func initializes used services only once.
 func Init() {
 	once.Do(func() {
-		Authorization = Auth()
+		KeyCache = NewApiKeyCache(
+			config.Get().ApiKeyCache.TTL,
+			config.Get().ApiKeyCache.NegativeTTL,
+			config.Get().ApiKeyCache.Size,
+		)
+		Authorization = Auth(KeyCache)
+		Devices = Device()
+		ApiKeys = ApiKey()
 		TollEvents = TollEvent()
-		Billing = BillingWorkers(workesCount, bufferSize)
+		DailyFees = DailyFee(config.Get().Billing.Location())
+		Billing = BillingWorkers(
+			workesCount,
+			config.Get().Billing.Location(),
+			config.Get().Billing.MaxBacklog,
+			config.Get().Validation.MaxStay,
+		)
 	})
 }
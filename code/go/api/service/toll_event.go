@@ -13,6 +13,8 @@
 	// TollEventService interface with method definitions.
 	TollEventService interface {
 		Record(ctx context.Context, event *types.TollEvent) error
+		RecordBatch(ctx context.Context, events []*types.TollEvent) error
+		GetEvents(ctx context.Context, filter types.TollEventFilter) (*types.TollEventPage, error)
 	}
 
 	event struct {
@@ -33,3 +35,30 @@ func TollEvent() TollEventService {
 func (svc *event) Record(ctx context.Context, event *types.TollEvent) error {
 	return svc.events.Record(ctx, event)
 }
+
+// RecordBatch stores all toll events at once.
+func (svc *event) RecordBatch(ctx context.Context, events []*types.TollEvent) error {
+	return svc.events.RecordBatch(ctx, events)
+}
+
+// GetEvents returns page of toll events matching the filter.
+func (svc *event) GetEvents(ctx context.Context, filter types.TollEventFilter) (*types.TollEventPage, error) {
+	limit := filter.Limit
+
+	// One extra event tells if there is a next page.
+	filter.Limit++
+
+	events, err := svc.events.GetEvents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &types.TollEventPage{Events: events}
+
+	if len(events) > limit {
+		page.Events = events[:limit]
+		page.Next = types.CursorOf(page.Events[limit-1])
+	}
+
+	return page, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	repository "toll/api/repository/mocks"
+	"toll/api/types"
+	"toll/internal/test"
+)
+
+func TestTollEventGetEvents(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 2, 3, 7, 0, 0, 0, time.UTC)
+
+	stored := []*types.TollEvent{
+		{CreatedAt: start, LicensePlate: "ABC123", EventStart: start, VehicleType: types.Car},
+		{CreatedAt: start, LicensePlate: "XYZ789", EventStart: start, VehicleType: types.Car},
+		{CreatedAt: start, LicensePlate: "ABC123", EventStart: start.Add(time.Hour), VehicleType: types.Car},
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+	}{
+		{"last page", 3},
+		{"more pages", 2},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Define mocked executions.
+			events := repository.NewMockTollEventRepository(t)
+			events.EXPECT().
+				GetEvents(t.Context(), mock.MatchedBy(func(f types.TollEventFilter) bool {
+					return f.Limit == tc.limit+1
+				})).
+				Return(stored[:min(len(stored), tc.limit+1)], nil)
+
+			svc := &event{events: events}
+
+			page, err := svc.GetEvents(t.Context(), types.TollEventFilter{Limit: tc.limit})
+
+			test.Match(t, len(page.Events), page.Next, err)
+		})
+	}
+}
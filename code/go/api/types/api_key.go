@@ -1,15 +1,91 @@
 package types
 
 import (
+	"database/sql/driver"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// Scope permits API key to call a group of operations.
+type Scope string
+
+// Scope values.
+const (
+	ScopeEventsWrite Scope = "events:write"
+	ScopeEventsRead  Scope = "events:read"
+	ScopeFeesRead    Scope = "fees:read"
+	ScopeKeysAdmin   Scope = "keys:admin"
+)
+
+// Scopes is stored as text array.
+type Scopes []Scope
+
 // ApiKey struct definition.
 type ApiKey struct {
-	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
-	KeyHash   string    `json:"key_hash" db:"key_hash"`
-	Id        uuid.UUID `json:"id" db:"id"`
-	SystemKey bool      `json:"system_key" db:"system_key"`
+	ExpiresAt    time.Time  `json:"expires_at" db:"expires_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt    *time.Time `json:"revoked_at" db:"revoked_at"`
+	KeyHash      string     `json:"key_hash" db:"key_hash"`
+	Description  string     `json:"description" db:"description"`
+	Organisation string     `json:"organisation" db:"organisation"`
+	GantryId     *string    `json:"gantry_id" db:"gantry_id"`
+	Scopes       Scopes     `json:"scopes" db:"scopes"`
+	Id           uuid.UUID  `json:"id" db:"id"`
+	SystemKey    bool       `json:"system_key" db:"system_key"`
+}
+
+// IsRevoked checks if the key has been revoked.
+func (k ApiKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// HasScope checks if the key is granted the scope. Keys can be administered
+// only with system keys, whatever their scopes are.
+func (k ApiKey) HasScope(scope Scope) bool {
+	if scope == ScopeKeysAdmin && !k.SystemKey {
+		return false
+	}
+
+	return slices.Contains(k.Scopes, scope)
+}
+
+// IsKnown checks if the scope is one of the defined scopes.
+func (s Scope) IsKnown() bool {
+	switch s {
+	case ScopeEventsWrite, ScopeEventsRead, ScopeFeesRead, ScopeKeysAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// Value implements the driver.Valuer interface.
+func (s Scopes) Value() (driver.Value, error) {
+	arr := make(pq.StringArray, 0, len(s))
+
+	for _, scope := range s {
+		arr = append(arr, string(scope))
+	}
+
+	return arr.Value()
+}
+
+// Scan implements the sql.Scanner interface.
+func (s *Scopes) Scan(src interface{}) error {
+	var arr pq.StringArray
+
+	if err := arr.Scan(src); err != nil {
+		return err
+	}
+
+	*s = make(Scopes, 0, len(arr))
+
+	for _, scope := range arr {
+		*s = append(*s, Scope(scope))
+	}
+
+	return nil
 }
@@ -0,0 +1,50 @@
+package types
+
+import (
+	"testing"
+
+	"toll/internal/test"
+)
+
+func TestApiKey_HasScope(t *testing.T) {
+	t.Parallel()
+
+	scopes := Scopes{ScopeEventsWrite, ScopeKeysAdmin}
+
+	tests := []struct {
+		name string
+		key  ApiKey
+	}{
+		{"client key", ApiKey{Scopes: scopes}},
+		{"system key", ApiKey{Scopes: scopes, SystemKey: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			test.Match(t,
+				tt.key.HasScope(ScopeEventsWrite),
+				tt.key.HasScope(ScopeFeesRead),
+				tt.key.HasScope(ScopeKeysAdmin),
+			)
+		})
+	}
+}
+
+func TestScopes_ValueScan(t *testing.T) {
+	t.Parallel()
+
+	scopes := Scopes{ScopeEventsWrite, ScopeFeesRead}
+
+	value, err := scopes.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var scanned Scopes
+
+	err = scanned.Scan([]byte(value.(string)))
+
+	test.Match(t, value, scanned, err)
+}
@@ -0,0 +1,26 @@
+package types
+
+import (
+	"time"
+)
+
+// BillingJobStatus is a string-based enum.
+type BillingJobStatus string
+
+// Enum values.
+const (
+	BillingJobPending BillingJobStatus = "pending"
+	BillingJobDead    BillingJobStatus = "dead"
+)
+
+// BillingJob is a persisted request to recompute daily fee of a license plate.
+type BillingJob struct {
+	BillingDate  time.Time        `json:"billing_date" db:"billing_date"`
+	AvailableAt  time.Time        `json:"available_at" db:"available_at"`
+	CreatedAt    time.Time        `json:"created_at" db:"created_at"`
+	LastError    *string          `json:"last_error" db:"last_error"`
+	LicensePlate string           `json:"license_plate" db:"license_plate"`
+	Status       BillingJobStatus `json:"status" db:"status"`
+	Id           int64            `json:"id" db:"id"`
+	Attempts     int              `json:"attempts" db:"attempts"`
+}
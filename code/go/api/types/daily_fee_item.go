@@ -0,0 +1,63 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type (
+	// DailyFeeItem explains one fee window of a daily charge. Window fee is the highest
+	// fee of its events, charged fee is lower when the daily cap clipped it.
+	DailyFeeItem struct {
+		Date         time.Time     `json:"date" db:"date"`
+		LicensePlate string        `json:"license_plate" db:"license_plate"`
+		WindowStart  time.Time     `json:"window_start" db:"window_start"`
+		Events       FeeItemEvents `json:"events" db:"events"`
+		Fee          int           `json:"fee" db:"fee"`
+		Charged      int           `json:"charged" db:"charged"`
+		Capped       bool          `json:"capped" db:"capped"`
+	}
+
+	// FeeItemEvent is a toll event priced within a fee window.
+	FeeItemEvent struct {
+		EventStart  time.Time   `json:"event_start"`
+		EventStop   *time.Time  `json:"event_stop,omitempty"`
+		VehicleType VehicleType `json:"vehicle_type"`
+		Fee         int         `json:"fee"`
+	}
+
+	// FeeItemEvents is stored as JSON array.
+	FeeItemEvents []FeeItemEvent
+)
+
+// Value implements the driver.Valuer interface.
+func (e FeeItemEvents) Value() (driver.Value, error) {
+	if e == nil {
+		e = FeeItemEvents{}
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface.
+func (e *FeeItemEvents) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*e = nil
+
+		return nil
+	case []byte:
+		return json.Unmarshal(v, e)
+	case string:
+		return json.Unmarshal([]byte(v), e)
+	default:
+		return fmt.Errorf("unsupported fee item events type %T", src)
+	}
+}
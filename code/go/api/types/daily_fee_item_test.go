@@ -0,0 +1,38 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"toll/internal/test"
+)
+
+func TestFeeItemEvents_ValueScan(t *testing.T) {
+	t.Parallel()
+
+	events := FeeItemEvents{
+		{EventStart: time.Date(2025, time.February, 3, 7, 0, 0, 0, time.UTC), VehicleType: Car, Fee: 18},
+		{EventStart: time.Date(2025, time.February, 3, 7, 30, 0, 0, time.UTC), VehicleType: Truck, Fee: 18},
+	}
+
+	value, err := events.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var scanned FeeItemEvents
+
+	err = scanned.Scan([]byte(value.(string)))
+
+	test.Match(t, value, scanned, err)
+}
+
+func TestFeeItemEvents_ScanUnsupported(t *testing.T) {
+	t.Parallel()
+
+	var scanned FeeItemEvents
+
+	err := scanned.Scan(42)
+
+	test.Match(t, err)
+}
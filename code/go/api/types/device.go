@@ -0,0 +1,37 @@
+package types
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Device is a gantry signing its requests with a shared secret or connecting
+// with a client certificate.
+type Device struct {
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	RevokedAt    *time.Time `json:"revoked_at" db:"revoked_at"`
+	GantryId     string     `json:"gantry_id" db:"gantry_id"`
+	Secret       string     `json:"-" db:"secret"`
+	Description  string     `json:"description" db:"description"`
+	Organisation string     `json:"organisation" db:"organisation"`
+	Scopes       Scopes     `json:"scopes" db:"scopes"`
+	CertSpki     *string    `json:"cert_spki" db:"cert_spki"`
+	CertSubject  *string    `json:"cert_subject" db:"cert_subject"`
+	Id           uuid.UUID  `json:"id" db:"id"`
+}
+
+// IsRevoked checks if the device has been revoked.
+func (d Device) IsRevoked() bool {
+	return d.RevokedAt != nil
+}
+
+// HasScope checks if the device is granted the scope. Devices never administer keys.
+func (d Device) HasScope(scope Scope) bool {
+	if scope == ScopeKeysAdmin {
+		return false
+	}
+
+	return slices.Contains(d.Scopes, scope)
+}
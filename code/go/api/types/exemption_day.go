@@ -0,0 +1,11 @@
+package types
+
+import (
+	"time"
+)
+
+// ExemptionDay holds additional toll-free date, e.g. a municipal exemption.
+type ExemptionDay struct {
+	Date        time.Time `json:"date" db:"date"`
+	Description string    `json:"description" db:"description"`
+}
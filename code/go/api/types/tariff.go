@@ -0,0 +1,67 @@
+package types
+
+import (
+	"time"
+)
+
+// Tariff holds toll fee schedule valid for a specific time period.
+type Tariff struct {
+	ValidFrom   time.Time   `json:"valid_from" db:"valid_from"`
+	ValidTo     *time.Time  `json:"valid_to" db:"valid_to"`
+	Fees        []TariffFee `json:"fees" db:"-"`
+	Id          int64       `json:"id" db:"id"`
+	MaxDailyFee int         `json:"max_daily_fee" db:"max_daily_fee"`
+}
+
+// TariffFee holds fee for a time of day range (inclusive).
+type TariffFee struct {
+	TariffId    int64 `json:"tariff_id" db:"tariff_id"`
+	StartHour   int   `json:"start_hour" db:"start_hour"`
+	StartMinute int   `json:"start_minute" db:"start_minute"`
+	EndHour     int   `json:"end_hour" db:"end_hour"`
+	EndMinute   int   `json:"end_minute" db:"end_minute"`
+	Fee         int   `json:"fee" db:"fee"`
+}
+
+// Tariffs is a list of tariffs ordered by validity start.
+type Tariffs []*Tariff
+
+// IsValidAt checks if tariff is valid at the provided time.
+func (t *Tariff) IsValidAt(at time.Time) bool {
+	if at.Before(t.ValidFrom) {
+		return false
+	}
+
+	return t.ValidTo == nil || at.Before(*t.ValidTo)
+}
+
+// FeeAt returns fee for the time of day of the provided time.
+func (t *Tariff) FeeAt(at time.Time) int {
+	hour, minute := at.Hour(), at.Minute()
+
+	for _, tf := range t.Fees {
+		if tf.Covers(hour, minute) {
+			return tf.Fee
+		}
+	}
+
+	return 0
+}
+
+// Covers checks if time of day is inside of the fee range.
+func (tf TariffFee) Covers(hour, minute int) bool {
+	return (hour > tf.StartHour || (hour == tf.StartHour && minute >= tf.StartMinute)) &&
+		(hour < tf.EndHour || (hour == tf.EndHour && minute <= tf.EndMinute))
+}
+
+// At returns tariff valid at the provided time or nil when there is none.
+func (ts Tariffs) At(at time.Time) *Tariff {
+	// Later tariffs take precedence over overlapping earlier ones.
+	for i := len(ts) - 1; i >= 0; i-- {
+		if ts[i].IsValidAt(at) {
+			return ts[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"toll/internal/test"
+)
+
+func TestTariffs_At(t *testing.T) {
+	t.Parallel()
+
+	change := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	tariffs := Tariffs{
+		{Id: 1, ValidFrom: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), ValidTo: &change},
+		{Id: 2, ValidFrom: change},
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+	}{
+		{"before first tariff", time.Date(2024, time.December, 31, 23, 59, 0, 0, time.UTC)},
+		{"first tariff", time.Date(2025, time.February, 28, 23, 59, 0, 0, time.UTC)},
+		{"second tariff at change", change},
+		{"open ended tariff", time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var id int64
+			if tariff := tariffs.At(tt.at); tariff != nil {
+				id = tariff.Id
+			}
+
+			test.Match(t, tt.at, id)
+		})
+	}
+}
@@ -1,7 +1,12 @@
 package types
 
 import (
+	"encoding/base64"
+	"errors"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // VehicleType is a string-based enum.
@@ -21,12 +26,26 @@
 )
 
 type TollEvent struct {
-	CreatedAt    time.Time   `json:"created_at" db:"created_at"`
-	LicensePlate string      `json:"license_plate" db:"license_plate"`
-	EventStart   time.Time   `json:"event_start" db:"event_start"`
-	EventStop    time.Time   `json:"event_stop" db:"event_stop"`
-	VehicleType  VehicleType `json:"vehicle_type" db:"vehicle_type"`
-	Billed       bool        `json:"billed" db:"billed"`
+	EventId      uuid.NullUUID `json:"event_id" db:"event_id"`
+	CreatedAt    time.Time     `json:"created_at" db:"created_at"`
+	LicensePlate string        `json:"license_plate" db:"license_plate"`
+	EventStart   time.Time     `json:"event_start" db:"event_start"`
+	EventStop    *time.Time    `json:"event_stop" db:"event_stop"`
+	VehicleType  VehicleType   `json:"vehicle_type" db:"vehicle_type"`
+	Billed       bool          `json:"billed" db:"billed"`
+
+	// BodyHash is hash of the request body the event id was derived from by an
+	// idempotency key. It is stored with the claimed id only.
+	BodyHash []byte `json:"-" db:"-"`
+}
+
+// LastSeen returns event stop or event start when the stop is not known.
+func (t TollEvent) LastSeen() time.Time {
+	if t.EventStop == nil {
+		return t.EventStart
+	}
+
+	return *t.EventStop
 }
 
 // IsTollFree checks if a vehicle is toll-free.
@@ -41,7 +60,117 @@ func (t TollEvent) IsTollFree() bool {
 
 // DailyFee holds total daily amount for specific license plate.
 type DailyFee struct {
-	Date         time.Time `json:"date" db:"date"`
-	LicensePlate string    `json:"license_plate" db:"license_plate"`
-	Fee          int       `json:"fee" db:"fee"`
+	Date         time.Time      `json:"date" db:"date"`
+	LicensePlate string         `json:"license_plate" db:"license_plate"`
+	Fee          int            `json:"fee" db:"fee"`
+	Items        []DailyFeeItem `json:"items,omitempty" db:"-"`
+}
+
+// DailyFeeFilter selects daily fees of a license plate. From and To are inclusive
+// billing days, nil bounds are open.
+type DailyFeeFilter struct {
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+	Offset int
+}
+
+// DailyFeePage holds a page of daily fees ordered by billing day.
+type DailyFeePage struct {
+	Fees    []*DailyFee
+	HasMore bool
+}
+
+// TollEventFilter selects toll events, nil fields do not filter. From is inclusive
+// and To exclusive bound of event start.
+type TollEventFilter struct {
+	LicensePlate *string
+	VehicleType  *VehicleType
+	From         *time.Time
+	To           *time.Time
+	Billed       *bool
+	TollFree     *bool
+	After        *TollEventCursor
+	Limit        int
+}
+
+// TollEventPage holds a page of toll events ordered by event start.
+type TollEventPage struct {
+	Events []*TollEvent
+	Next   *TollEventCursor
+}
+
+// TollEventCursor points at the last toll event of a page. Events are ordered by
+// event start, created at, license plate and event id, which is unique.
+type TollEventCursor struct {
+	EventStart   time.Time
+	CreatedAt    time.Time
+	LicensePlate string
+	EventId      uuid.UUID
+}
+
+var (
+	// ErrInvalidCursor is returned when cursor cannot be decoded.
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrEventIdReused is returned when an event id was claimed by a request with a different body.
+	ErrEventIdReused = errors.New("idempotency key reused with a different body")
+)
+
+// CursorOf returns cursor pointing at the event.
+func CursorOf(event *TollEvent) *TollEventCursor {
+	return &TollEventCursor{
+		EventStart:   event.EventStart,
+		CreatedAt:    event.CreatedAt,
+		LicensePlate: event.LicensePlate,
+		EventId:      event.EventId.UUID,
+	}
+}
+
+// String encodes cursor as opaque URL safe string. License plate goes last, so
+// it may contain the separator.
+func (c TollEventCursor) String() string {
+	raw := strings.Join([]string{
+		c.EventStart.Format(time.RFC3339Nano),
+		c.CreatedAt.Format(time.RFC3339Nano),
+		c.EventId.String(),
+		c.LicensePlate,
+	}, "|")
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseTollEventCursor decodes cursor encoded by TollEventCursor.String.
+func ParseTollEventCursor(s string) (*TollEventCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), "|", 4)
+	if len(parts) != 4 {
+		return nil, ErrInvalidCursor
+	}
+
+	eventStart, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[1])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	eventId, err := uuid.Parse(parts[2])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &TollEventCursor{
+		EventStart:   eventStart,
+		CreatedAt:    createdAt,
+		LicensePlate: parts[3],
+		EventId:      eventId,
+	}, nil
 }
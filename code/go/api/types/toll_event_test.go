@@ -2,6 +2,10 @@
 
 import (
 	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
 	"toll/internal/test"
 )
 
@@ -36,3 +40,34 @@ func TestVehicleType_IsTollFree(t *testing.T) {
 		})
 	}
 }
+
+func TestTollEventCursor(t *testing.T) {
+	t.Parallel()
+
+	cursor := TollEventCursor{
+		EventStart:   time.Date(2025, time.February, 3, 7, 0, 0, 0, time.UTC),
+		CreatedAt:    time.Date(2025, time.February, 3, 7, 0, 1, 123456000, time.UTC),
+		LicensePlate: "ABC|123",
+		EventId:      uuid.MustParse("3b241101-e2bb-4255-8caf-4136c566a962"),
+	}
+
+	parsed, err := ParseTollEventCursor(cursor.String())
+
+	test.Match(t, cursor.String(), parsed, err)
+}
+
+func TestParseTollEventCursor_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{"not base64!", "bm90IGEgY3Vyc29y", "YWJjfGRlZnxnaGk"}
+
+	for _, tt := range tests {
+		t.Run(tt, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseTollEventCursor(tt)
+
+			test.Match(t, err)
+		})
+	}
+}
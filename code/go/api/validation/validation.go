@@ -0,0 +1,168 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"toll/api/types"
+)
+
+type (
+	// Rules configures toll event validation.
+	Rules struct {
+		// Countries lists country codes of accepted plate formats.
+		Countries []string
+		// MaxEventAge is the oldest event start accepted.
+		MaxEventAge time.Duration
+		// ClockSkew is tolerated difference between gantry and server clocks.
+		ClockSkew time.Duration
+		// MaxStay is the longest time between event start and stop.
+		MaxStay time.Duration
+	}
+
+	// FieldError describes invalid field of a request.
+	FieldError struct {
+		Field  string
+		Reason string
+	}
+
+	// Errors holds all invalid fields of a request.
+	Errors []FieldError
+
+	// Validator checks toll events against the rules.
+	Validator struct {
+		rules   Rules
+		formats []*regexp.Regexp
+	}
+)
+
+// Field names as sent by clients.
+const (
+	FieldLicensePlate = "license_plate"
+	FieldEventStart   = "event_start"
+	FieldEventStop    = "event_stop"
+)
+
+// plateFormats holds formats of normalised standard plates per country code.
+var plateFormats = map[string]*regexp.Regexp{
+	"SE": regexp.MustCompile(`^[A-Z]{3}[0-9]{2}[A-Z0-9]$`),
+	"NO": regexp.MustCompile(`^[A-Z]{2}[0-9]{4,5}$`),
+	"DK": regexp.MustCompile(`^[A-Z]{2}[0-9]{5}$`),
+	"FI": regexp.MustCompile(`^[A-Z]{2,3}[0-9]{1,3}$`),
+	"DE": regexp.MustCompile(`^[A-Z]{1,3}[A-Z]{1,2}[0-9]{1,4}[EH]?$`),
+	"FR": regexp.MustCompile(`^[A-Z]{2}[0-9]{3}[A-Z]{2}$`),
+}
+
+// anyPlate is format of plates without a country specific format, e.g. foreign ones.
+var anyPlate = regexp.MustCompile(`^[\p{Lu}0-9]{2,10}$`)
+
+// IsKnownCountry checks if there is a plate format for the country code.
+func IsKnownCountry(country string) bool {
+	_, ok := plateFormats[country]
+
+	return ok
+}
+
+// NormalizePlate returns upper case plate without spaces, hyphens and dots,
+// e.g. "abc 123" and "ABC-123" both become "ABC123".
+func NormalizePlate(plate string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' || r == '.' {
+			return -1
+		}
+
+		return unicode.ToUpper(r)
+	}, plate)
+}
+
+// New func returns Validator for the rules. Unknown countries are ignored.
+func New(rules Rules) *Validator {
+	v := &Validator{rules: rules}
+
+	for _, country := range rules.Countries {
+		if format, ok := plateFormats[country]; ok {
+			v.formats = append(v.formats, format)
+		}
+	}
+
+	return v
+}
+
+// TollEvent normalises license plate of the event and returns its invalid fields.
+func (v *Validator) TollEvent(event *types.TollEvent, now time.Time) Errors {
+	var errs Errors
+
+	event.LicensePlate = NormalizePlate(event.LicensePlate)
+
+	if reason := v.plate(event); reason != "" {
+		errs = append(errs, FieldError{Field: FieldLicensePlate, Reason: reason})
+	}
+
+	latest := now.Add(v.rules.ClockSkew)
+
+	switch {
+	case event.EventStart.After(latest):
+		errs = append(errs, FieldError{Field: FieldEventStart, Reason: "toll event start date should be in the past"})
+	case v.rules.MaxEventAge > 0 && event.EventStart.Before(now.Add(-v.rules.MaxEventAge)):
+		errs = append(errs, FieldError{
+			Field:  FieldEventStart,
+			Reason: fmt.Sprintf("toll event start date should not be older than %s", v.rules.MaxEventAge),
+		})
+	}
+
+	if stop := event.EventStop; stop != nil {
+		switch {
+		case !stop.After(event.EventStart):
+			errs = append(errs, FieldError{Field: FieldEventStop, Reason: "toll event stop date should be after start date"})
+		case stop.After(latest):
+			errs = append(errs, FieldError{Field: FieldEventStop, Reason: "toll event stop date should be in the past"})
+		case v.rules.MaxStay > 0 && stop.Sub(event.EventStart) > v.rules.MaxStay:
+			errs = append(errs, FieldError{
+				Field:  FieldEventStop,
+				Reason: fmt.Sprintf("toll event stop date should not be more than %s after start date", v.rules.MaxStay),
+			})
+		}
+	}
+
+	return errs
+}
+
+// plate returns reason why normalised plate of the event is invalid, if any.
+// Plates of foreign and diplomat vehicles do not follow the country formats.
+func (v *Validator) plate(event *types.TollEvent) string {
+	plate := event.LicensePlate
+
+	if plate == "" {
+		return "license plate is required"
+	}
+
+	if !anyPlate.MatchString(plate) {
+		return "license plate should have 2 to 10 letters or digits"
+	}
+
+	if event.VehicleType == types.Foreign || event.VehicleType == types.Diplomat || len(v.formats) == 0 {
+		return ""
+	}
+
+	for _, format := range v.formats {
+		if format.MatchString(plate) {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("license plate does not match plate formats of %s", strings.Join(v.rules.Countries, ", "))
+}
+
+// Error returns all invalid fields with reasons.
+func (e Errors) Error() string {
+	reasons := make([]string, 0, len(e))
+
+	for _, fe := range e {
+		reasons = append(reasons, fe.Field+": "+fe.Reason)
+	}
+
+	return strings.Join(reasons, "; ")
+}
@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"toll/api/types"
+	"toll/internal/test"
+)
+
+func TestNormalizePlate(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{"abc 123", "ABC-123", " a.b.c 1 2 3 ", "ABC123"}
+
+	for _, plate := range tests {
+		t.Run(plate, func(t *testing.T) {
+			t.Parallel()
+
+			test.Match(t, NormalizePlate(plate))
+		})
+	}
+}
+
+func TestValidatorTollEvent(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.February, 3, 12, 0, 0, 0, time.UTC)
+	stop := now.Add(-time.Hour)
+	early := now.Add(-2 * time.Hour)
+
+	v := New(Rules{
+		Countries:   []string{"SE", "NO"},
+		MaxEventAge: 24 * time.Hour,
+		ClockSkew:   time.Minute,
+		MaxStay:     4 * time.Hour,
+	})
+
+	tests := []struct {
+		name  string
+		event types.TollEvent
+	}{
+		{"valid", types.TollEvent{LicensePlate: "abc 12a", EventStart: now.Add(-2 * time.Hour), EventStop: &stop, VehicleType: types.Car}},
+		{"norwegian", types.TollEvent{LicensePlate: "AB-12345", EventStart: now, VehicleType: types.Car}},
+		{"foreign", types.TollEvent{LicensePlate: "W12345X", EventStart: now, VehicleType: types.Foreign}},
+		{"clock skew", types.TollEvent{LicensePlate: "ABC123", EventStart: now.Add(30 * time.Second), VehicleType: types.Car}},
+		{"unknown format", types.TollEvent{LicensePlate: "W12345X", EventStart: now, VehicleType: types.Car}},
+		{"no plate", types.TollEvent{LicensePlate: " - ", EventStart: now, VehicleType: types.Car}},
+		{"bad characters", types.TollEvent{LicensePlate: "ABC/123", EventStart: now, VehicleType: types.Foreign}},
+		{"future", types.TollEvent{LicensePlate: "ABC123", EventStart: now.Add(time.Hour), VehicleType: types.Car}},
+		{"too old", types.TollEvent{LicensePlate: "ABC123", EventStart: now.Add(-48 * time.Hour), VehicleType: types.Car}},
+		{"stop before start", types.TollEvent{LicensePlate: "abc", EventStart: now.Add(-time.Minute), EventStop: &early, VehicleType: types.Car}},
+		{"stay too long", types.TollEvent{LicensePlate: "ABC123", EventStart: now.Add(-6 * time.Hour), EventStop: &stop, VehicleType: types.Car}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			event := tt.event
+			errs := v.TollEvent(&event, now)
+
+			test.Match(t, event.LicensePlate, errs)
+		})
+	}
+}
+
+func TestValidatorTollEvent_AnyCountry(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, time.February, 3, 12, 0, 0, 0, time.UTC)
+
+	event := types.TollEvent{LicensePlate: "w 12345 x", EventStart: now.Add(-24 * 365 * time.Hour), VehicleType: types.Car}
+
+	errs := New(Rules{}).TollEvent(&event, now)
+
+	test.Match(t, event.LicensePlate, errs)
+}
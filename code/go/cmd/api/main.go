@@ -2,6 +2,7 @@
 
 import (
 	"time"
+	_ "time/tzdata"
 
 	"github.com/jnovack/flag"
 	_ "github.com/joho/godotenv/autoload"
@@ -29,6 +30,14 @@ func main() {
 		log.WithFields(errlog.StackLog(err)).Fatale(err, "error initializing API service")
 	}
 
+	if rebilled, err := service.Rebill(); rebilled {
+		if err != nil {
+			log.WithFields(errlog.StackLog(err)).Fatale(err, "error rebilling")
+		}
+
+		return
+	}
+
 	log.Info("starting API service")
 
 	if err := service.Start(); err != nil {
@@ -29,6 +29,8 @@
 
 	// TX interface defines possible operations on database transaction.
 	TX interface {
+		Get(dest interface{}, query string, args ...interface{}) error
+		Select(dest interface{}, query string, args ...interface{}) error
 		Exec(query string, args ...interface{}) (res sql.Result, err error)
 		NamedExec(query string, arg interface{}) (res sql.Result, err error)
 	}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"toll/internal/errlog"
+	"toll/internal/log"
+)
+
+const (
+	listenMinBackoff = time.Second
+	listenMaxBackoff = 30 * time.Second
+)
+
+type (
+	// Listener receives notifications sent to a database channel.
+	Listener interface {
+		// Notify is called with the payload of every notification.
+		Notify(payload string)
+
+		// Reset is called after every (re)connect since notifications may have been missed.
+		Reset()
+	}
+)
+
+// Listen delivers notifications of the channel to the listener until ctx is done.
+// Lost connections are re-established with backoff. Only the pgx driver supports it.
+func Listen(ctx context.Context, channel string, l Listener, name ...string) error {
+	names := name
+	if len(names) == 0 {
+		names = []string{"db"}
+	}
+
+	crd, err := handle.getCredentials(names[0])
+	if err != nil {
+		return errlog.Error(err)
+	}
+
+	if crd.Driver != "pgx" {
+		return errlog.Errorf("database driver %s does not support notifications", crd.Driver)
+	}
+
+	cfg, err := pgx.ParseConfig(crd.DSN)
+	if err != nil {
+		return errlog.Error(err)
+	}
+
+	if crd.TLS {
+		cfg.TLSConfig = &tlsConfig
+	}
+
+	backoff := listenMinBackoff
+
+	for ctx.Err() == nil {
+		err := listen(ctx, cfg, channel, l, func() { backoff = listenMinBackoff })
+		if ctx.Err() != nil {
+			break
+		}
+
+		log.WithFields(log.Fields{"channel": channel, "retry": backoff}).
+			Warne(err, "database notifications interrupted")
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, listenMaxBackoff)
+	}
+
+	return nil
+}
+
+// listen waits for notifications on a single connection until it fails.
+func listen(ctx context.Context, cfg *pgx.ConnConfig, channel string, l Listener, connected func()) error {
+	conn, err := pgx.ConnectConfig(ctx, cfg)
+	if err != nil {
+		return errlog.Error(err)
+	}
+
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return errlog.Error(err)
+	}
+
+	connected()
+	l.Reset()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return errlog.Error(err)
+		}
+
+		l.Notify(n.Payload)
+	}
+}
@@ -1,8 +1,12 @@
 package request
 
 import (
+	"context"
+	"maps"
 	"net/http"
 	"os"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
@@ -17,6 +21,12 @@
 	logger struct {
 		zerolog zerolog.Logger
 	}
+
+	// logFields holds fields added to the log entry while the request is handled.
+	logFields struct {
+		mu     sync.Mutex
+		values map[string]string
+	}
 )
 
 var (
@@ -39,6 +49,9 @@ func Logger(next http.Handler) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
 
+		fields := &logFields{values: make(map[string]string)}
+		r = r.WithContext(context.WithValue(r.Context(), logFieldsKey, fields))
+
 		t1 := time.Now()
 		defer func() {
 			event := h.zerolog.Log()
@@ -52,6 +65,12 @@ func Logger(next http.Handler) http.Handler {
 			event = event.Float64("duration", time.Since(t1).Seconds())
 			event = event.Str("from", r.RemoteAddr)
 
+			fields.mu.Lock()
+			for _, k := range slices.Sorted(maps.Keys(fields.values)) {
+				event = event.Str(k, fields.values[k])
+			}
+			fields.mu.Unlock()
+
 			event.Send()
 		}()
 
@@ -60,3 +79,16 @@ func Logger(next http.Handler) http.Handler {
 
 	return http.HandlerFunc(fn)
 }
+
+// SetLogFields adds fields to the log entry of the request in the context.
+func SetLogFields(ctx context.Context, values map[string]string) {
+	fields, ok := ctx.Value(logFieldsKey).(*logFields)
+	if !ok {
+		return
+	}
+
+	fields.mu.Lock()
+	defer fields.mu.Unlock()
+
+	maps.Copy(fields.values, values)
+}
@@ -24,6 +24,7 @@ func TestRequest_Logger(t *testing.T) {
 	reqId := "test"
 
 	mockHandler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		SetLogFields(req.Context(), map[string]string{"key_id": "test-key"})
 		rw.WriteHeader(http.StatusOK)
 	})
 
@@ -87,4 +88,11 @@ func TestRequest_Logger(t *testing.T) {
 		parsedLog["uri"],
 		"expected = http://localhost, got = %v", parsedLog["uri"],
 	)
+
+	assert.Equal(
+		t,
+		"test-key",
+		parsedLog["key_id"],
+		"expected = test-key, got = %v", parsedLog["key_id"],
+	)
 }
@@ -11,6 +11,7 @@
 
 const (
 	requestIdKey ctxKey = "request_id"
+	logFieldsKey ctxKey = "log_fields"
 	requestId    string = "X-Request-ID"
 )
 
@@ -4,6 +4,7 @@
 	"os"
 	"os/signal"
 	"sync"
+	"syscall"
 )
 
 var (
@@ -16,7 +17,7 @@
 func New() Ctx {
 	once.Do(func() {
 		c = make(chan os.Signal, 1)
-		signal.Notify(c, os.Interrupt)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 
 		dc := make(chan struct{})
 		d = dc
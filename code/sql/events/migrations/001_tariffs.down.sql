@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS tariff_fees;
+
+DROP TABLE IF EXISTS tariffs;
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS tariffs (
+    id             BIGSERIAL NOT NULL,
+    valid_from     TIMESTAMPTZ NOT NULL,
+    valid_to       TIMESTAMPTZ,
+    max_daily_fee  INTEGER NOT NULL,
+
+    PRIMARY KEY (id),
+    CONSTRAINT valid_period CHECK (valid_to IS NULL OR valid_to > valid_from)
+);
+
+CREATE TABLE IF NOT EXISTS tariff_fees (
+    tariff_id     BIGINT NOT NULL REFERENCES tariffs (id) ON DELETE CASCADE,
+    start_hour    SMALLINT NOT NULL,
+    start_minute  SMALLINT NOT NULL,
+    end_hour      SMALLINT NOT NULL,
+    end_minute    SMALLINT NOT NULL,
+    fee           INTEGER NOT NULL,
+
+    PRIMARY KEY (tariff_id, start_hour, start_minute)
+);
+
+-- Seed tariff matching previously hard-coded toll fees.
+WITH t AS (
+    INSERT INTO tariffs (valid_from, valid_to, max_daily_fee)
+    VALUES ('1970-01-01T00:00:00Z', NULL, 60)
+    RETURNING id
+)
+INSERT INTO tariff_fees (tariff_id, start_hour, start_minute, end_hour, end_minute, fee)
+SELECT t.id, f.start_hour, f.start_minute, f.end_hour, f.end_minute, f.fee
+FROM t, (VALUES
+    (6, 0, 6, 29, 8),
+    (6, 30, 6, 59, 13),
+    (7, 0, 7, 59, 18),
+    (8, 0, 8, 29, 13),
+    (8, 30, 14, 59, 8),
+    (15, 0, 15, 29, 13),
+    (15, 30, 16, 59, 18),
+    (17, 0, 17, 59, 13),
+    (18, 0, 18, 29, 8)
+) AS f (start_hour, start_minute, end_hour, end_minute, fee);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS exemption_days;
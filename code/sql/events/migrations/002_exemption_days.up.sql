@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS exemption_days (
+    date         DATE NOT NULL,
+    description  TEXT NOT NULL DEFAULT '',
+
+    PRIMARY KEY (date)
+);
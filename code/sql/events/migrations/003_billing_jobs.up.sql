@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS billing_jobs (
+    id             BIGSERIAL NOT NULL,
+    license_plate  TEXT NOT NULL,
+    billing_date   TIMESTAMPTZ NOT NULL,
+    status         TEXT NOT NULL DEFAULT 'pending',
+    attempts       INTEGER NOT NULL DEFAULT 0,
+    last_error     TEXT,
+    available_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_billing_jobs_pending
+    ON billing_jobs (available_at)
+    WHERE status = 'pending';
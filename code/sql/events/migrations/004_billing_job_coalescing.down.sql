@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_billing_jobs_unclaimed;
@@ -0,0 +1,14 @@
+-- Keep a single unclaimed job per license plate and billing date.
+DELETE FROM billing_jobs a
+USING billing_jobs b
+WHERE a.status = 'pending'
+  AND a.attempts = 0
+  AND b.status = 'pending'
+  AND b.attempts = 0
+  AND a.license_plate = b.license_plate
+  AND a.billing_date = b.billing_date
+  AND a.id > b.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_billing_jobs_unclaimed
+    ON billing_jobs (license_plate, billing_date)
+    WHERE status = 'pending' AND attempts = 0;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS daily_toll_fee_items;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS daily_toll_fee_items (
+    date           TIMESTAMPTZ NOT NULL,
+    license_plate  TEXT NOT NULL,
+    window_start   TIMESTAMPTZ NOT NULL,
+    events         JSONB NOT NULL,
+    fee            INTEGER NOT NULL,
+    charged        INTEGER NOT NULL,
+    capped         BOOLEAN NOT NULL DEFAULT FALSE,
+
+    PRIMARY KEY (date, license_plate, window_start),
+    FOREIGN KEY (date, license_plate)
+        REFERENCES daily_toll_fees (date, license_plate)
+        ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_daily_toll_fees_plate_date;
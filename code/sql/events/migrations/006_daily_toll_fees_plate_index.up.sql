@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_daily_toll_fees_plate_date
+    ON daily_toll_fees (license_plate, date);
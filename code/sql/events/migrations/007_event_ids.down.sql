@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS event_ids;
+
+ALTER TABLE events DROP COLUMN IF EXISTS event_id;
@@ -0,0 +1,10 @@
+ALTER TABLE events ADD COLUMN IF NOT EXISTS event_id UUID;
+
+-- Unique indexes on the events hypertable must include created_at, which differs
+-- between replays of an event, so client event ids are kept unique here instead.
+CREATE TABLE IF NOT EXISTS event_ids (
+    event_id    UUID NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (event_id)
+);
@@ -0,0 +1,3 @@
+UPDATE events SET event_stop = event_start + INTERVAL '1 hour' WHERE event_stop IS NULL;
+
+ALTER TABLE events ALTER COLUMN event_stop SET NOT NULL;
@@ -0,0 +1,5 @@
+-- Event stop is optional, missing stops were previously stored as start + 1 hour.
+ALTER TABLE events ALTER COLUMN event_stop DROP NOT NULL;
+
+-- Drop the made up stops, so they do not extend stays of billed events.
+UPDATE events SET event_stop = NULL WHERE event_stop = event_start + INTERVAL '1 hour';
@@ -0,0 +1,4 @@
+ALTER TABLE api_key DROP COLUMN IF EXISTS revoked_at;
+ALTER TABLE api_key DROP COLUMN IF EXISTS created_at;
+ALTER TABLE api_key DROP COLUMN IF EXISTS description;
+ALTER TABLE api_key DROP COLUMN IF EXISTS system_key;
@@ -0,0 +1,5 @@
+-- Only system keys may manage API keys, revoked keys are kept for auditing.
+ALTER TABLE api_key ADD COLUMN IF NOT EXISTS system_key BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE api_key ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
+ALTER TABLE api_key ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE api_key ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ;
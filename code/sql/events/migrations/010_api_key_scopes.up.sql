@@ -0,0 +1,5 @@
+ALTER TABLE api_key ADD COLUMN IF NOT EXISTS scopes TEXT[] NOT NULL DEFAULT '{}';
+
+-- Keys could call every operation before scopes, system keys could also manage keys.
+UPDATE api_key SET scopes = '{events:write,events:read,fees:read}' WHERE NOT system_key;
+UPDATE api_key SET scopes = '{events:write,events:read,fees:read,keys:admin}' WHERE system_key;
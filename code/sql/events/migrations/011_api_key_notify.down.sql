@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS api_key_changed ON api_key;
+DROP FUNCTION IF EXISTS notify_api_key_changed();
@@ -0,0 +1,22 @@
+-- Notify API instances of changed keys so they can drop cached lookups.
+CREATE OR REPLACE FUNCTION notify_api_key_changed() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM pg_notify('api_key_changed', OLD.key_hash);
+        RETURN OLD;
+    END IF;
+
+    IF TG_OP = 'UPDATE' AND OLD.key_hash <> NEW.key_hash THEN
+        PERFORM pg_notify('api_key_changed', OLD.key_hash);
+    END IF;
+
+    PERFORM pg_notify('api_key_changed', NEW.key_hash);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS api_key_changed ON api_key;
+
+CREATE TRIGGER api_key_changed
+    AFTER INSERT OR UPDATE OR DELETE ON api_key
+    FOR EACH ROW EXECUTE FUNCTION notify_api_key_changed();
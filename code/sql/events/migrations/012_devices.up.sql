@@ -0,0 +1,17 @@
+-- Gantry devices sign requests with their secret instead of sending an api key.
+CREATE TABLE IF NOT EXISTS device (
+    id           BYTEA NOT NULL,
+    gantry_id    TEXT NOT NULL,
+    -- Secret is stored in plaintext, HMAC verification needs it and it is only as
+    -- safe as the database. Accepted risk: restrict access to the table and its
+    -- backups, and rotate secrets of devices when either leaks.
+    secret       TEXT NOT NULL,
+    description  TEXT NOT NULL DEFAULT '',
+    scopes       TEXT[] NOT NULL DEFAULT '{events:write}',
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    revoked_at   TIMESTAMPTZ,
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_gantry ON device (gantry_id);
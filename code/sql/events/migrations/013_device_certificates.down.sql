@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_device_cert_subject;
+DROP INDEX IF EXISTS idx_device_cert_spki;
+
+ALTER TABLE device ALTER COLUMN secret DROP DEFAULT;
+ALTER TABLE device DROP COLUMN IF EXISTS cert_subject;
+ALTER TABLE device DROP COLUMN IF EXISTS cert_spki;
@@ -0,0 +1,7 @@
+-- Devices connecting over mutual TLS are matched by their client certificate.
+ALTER TABLE device ADD COLUMN IF NOT EXISTS cert_spki TEXT;
+ALTER TABLE device ADD COLUMN IF NOT EXISTS cert_subject TEXT;
+ALTER TABLE device ALTER COLUMN secret SET DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_device_cert_spki ON device (cert_spki) WHERE cert_spki IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_device_cert_subject ON device (cert_subject) WHERE cert_subject IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE device DROP COLUMN IF EXISTS organisation;
+ALTER TABLE api_key DROP COLUMN IF EXISTS gantry_id;
+ALTER TABLE api_key DROP COLUMN IF EXISTS organisation;
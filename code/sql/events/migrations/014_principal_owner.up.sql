@@ -0,0 +1,4 @@
+-- Callers are told apart by organisation and, for gantries, their gantry id.
+ALTER TABLE api_key ADD COLUMN IF NOT EXISTS organisation TEXT NOT NULL DEFAULT '';
+ALTER TABLE api_key ADD COLUMN IF NOT EXISTS gantry_id TEXT;
+ALTER TABLE device ADD COLUMN IF NOT EXISTS organisation TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE events ALTER COLUMN event_id DROP NOT NULL;
+ALTER TABLE events ALTER COLUMN event_id DROP DEFAULT;
@@ -0,0 +1,6 @@
+-- Every event gets an id, so it can break ties in keyset pagination. Ids of
+-- events recorded without a client id are generated and not claimed in event_ids.
+UPDATE events SET event_id = gen_random_uuid() WHERE event_id IS NULL;
+
+ALTER TABLE events ALTER COLUMN event_id SET DEFAULT gen_random_uuid();
+ALTER TABLE events ALTER COLUMN event_id SET NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE event_ids DROP COLUMN IF EXISTS body_hash;
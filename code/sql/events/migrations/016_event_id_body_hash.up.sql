@@ -0,0 +1,3 @@
+-- Hash of the request body claiming an idempotency key derived event id, so a key
+-- reused with a different body is rejected instead of silently ignored.
+ALTER TABLE event_ids ADD COLUMN IF NOT EXISTS body_hash BYTEA;
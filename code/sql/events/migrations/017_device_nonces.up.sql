@@ -0,0 +1,9 @@
+-- Nonces of device signatures, shared by API replicas, so a signed request cannot
+-- be replayed against another replica. Rows are kept until the signature expires.
+CREATE TABLE IF NOT EXISTS device_nonce (
+    device_id   BYTEA NOT NULL,
+    nonce       TEXT NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (device_id, nonce)
+);